@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -8,6 +9,8 @@ import (
 	"backend/internal/config"
 	"backend/internal/database"
 	"backend/internal/handlers"
+	"backend/internal/service"
+	"backend/pkg/utils"
 )
 
 func main() {
@@ -15,9 +18,16 @@ func main() {
 
 	// 1. 加载配置文件 (.env)
 	config.LoadConfig()
+	if err := config.AppConfig.Validate(); err != nil {
+		log.Fatalf("❌ Invalid configuration: %v", err)
+	}
+	if err := utils.SetTrustedProxies(config.AppConfig.TrustedProxies); err != nil {
+		log.Fatalf("❌ Invalid TRUSTED_PROXIES: %v", err)
+	}
 	fmt.Println("✅ Configuration loaded")
 	fmt.Printf("   - Database: %s:%s\n", config.AppConfig.DBHost, config.AppConfig.DBPort)
 	fmt.Printf("   - Server Port: %s\n", config.AppConfig.ServerPort)
+	fmt.Printf("   - Environment: %s\n", config.AppConfig.AppEnv)
 
 	// 2. 初始化数据库连接
 	if err := database.InitPostgreSQL(); err != nil {
@@ -25,17 +35,40 @@ func main() {
 	}
 	defer database.CloseDB()
 
+	// 设置配置中指定的管理员
+	if err := service.BootstrapAdmins(config.AppConfig.AdminEmails); err != nil {
+		log.Fatalf("❌ Failed to bootstrap admins: %v", err)
+	}
+
+	// 加载 JWT 签名密钥，并定期检查轮换
+	if err := service.LoadSigningKeys(); err != nil {
+		log.Fatalf("❌ Failed to load signing keys: %v", err)
+	}
+	go service.StartSigningKeyRefresher(context.Background())
+	fmt.Println("✅ Signing keys loaded")
+
 	// 3. 初始化 GCS
 	if err := database.InitGCS(); err != nil {
 		log.Fatalf("❌ Failed to initialize GCS: %v", err)
 	}
 	fmt.Println("✅ GCS initialized")
 
-	// 4. 初始化路由
+	// 4. 启动邮件发件箱投递任务，并让站内通知同时发送邮件
+	service.RegisterNotificationSink(service.EmailNotificationSink)
+	go service.StartEmailWorker(context.Background())
+	fmt.Printf("✅ Email worker started (SMTP %s:%s)\n", config.AppConfig.SMTPHost, config.AppConfig.SMTPPort)
+
+	// 定期删除冷静期已结束的账号
+	go service.StartAccountDeletionWorker(context.Background())
+
+	// 定期解除到期的临时封禁
+	go service.StartSuspensionWorker(context.Background())
+
+	// 5. 初始化路由
 	router := handlers.InitRouter()
 	fmt.Println("✅ Router initialized")
 
-	// 5. 启动 HTTP 服务器
+	// 6. 启动 HTTP 服务器
 	port := config.AppConfig.ServerPort //8080
 	fmt.Printf("🌐 Server listening on http://localhost:%s\n", port)
 	log.Fatal(http.ListenAndServe(":"+port, router))
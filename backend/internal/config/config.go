@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 
@@ -16,7 +17,7 @@ type Config struct {
 	DBPassword string
 	DBName     string
 
-	// JWT
+	// JWT（JWT_SECRET 用于加密数据库中保存的签名私钥）
 	JWTSecret string
 
 	// GCS
@@ -24,8 +25,29 @@ type Config struct {
 	GCSProjectID           string
 	GoogleCredentialsPath  string
 
+	// SMTP（本地开发可使用 MailHog：localhost:1025）
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	// OIDC 第三方登录（OIDCIssuer 为空时不启用）
+	OIDCIssuer       string
+	OIDCClientID     string
+	OIDCClientSecret string
+	OIDCRedirectURL  string // 前端的回调页面地址，需与提供方中登记的一致
+
 	// Server
+	AppEnv     string // 运行环境：development, production（未设置时按 production 处理）
 	ServerPort string
+	AppBaseURL string // 前端地址，用于生成邮件中的链接
+
+	// TrustedProxies 可信反向代理的IP或网段（逗号分隔），只信任来自这些地址的 X-Real-IP / X-Forwarded-For / X-Request-ID
+	TrustedProxies string
+
+	// Admin
+	AdminEmails string // 启动时设置为管理员的用户邮箱（逗号分隔）
 }
 
 var AppConfig *Config
@@ -53,9 +75,60 @@ func LoadConfig() {
 		GCSProjectID:          getEnv("GCS_PROJECT_ID", ""),
 		GoogleCredentialsPath: getEnv("GOOGLE_APPLICATION_CREDENTIALS", ""),
 
+		// SMTP
+		SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:     getEnv("SMTP_PORT", "1025"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", "SecondHand <no-reply@secondhand.local>"),
+
+		// OIDC
+		OIDCIssuer:       getEnv("OIDC_ISSUER", ""),
+		OIDCClientID:     getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:  getEnv("OIDC_REDIRECT_URL", "http://localhost:3000/auth/callback"),
+
 		// Server
+		AppEnv:     getEnv("APP_ENV", "production"),
 		ServerPort: getEnv("PORT", "8080"),
+		AppBaseURL: getEnv("APP_BASE_URL", "http://localhost:3000"),
+
+		// 默认只信任本机的 Nginx
+		TrustedProxies: getEnv("TRUSTED_PROXIES", "127.0.0.1,::1"),
+
+		// Admin
+		AdminEmails: getEnv("ADMIN_EMAILS", ""),
+	}
+}
+
+// placeholderSecrets 示例配置中的占位密钥，不能在非开发环境中使用
+var placeholderSecrets = map[string]bool{
+	"your-secret-key-change-this":                 true,
+	"your-secret-key-change-this-in-production":   true,
+	"dev-jwt-secret-key-do-not-use-in-production": true,
+}
+
+// IsDevelopment 是否为开发环境
+func (c *Config) IsDevelopment() bool {
+	return c.AppEnv == "development"
+}
+
+// Validate 检查配置，非开发环境下拒绝使用占位密钥或过短的密钥
+// 未设置 APP_ENV 时按生产环境检查，避免忘记配置时带着占位密钥上线
+func (c *Config) Validate() error {
+	if c.AppEnv != "development" && c.AppEnv != "production" {
+		return fmt.Errorf("APP_ENV must be development or production, got %q", c.AppEnv)
+	}
+	if c.IsDevelopment() {
+		return nil
+	}
+	if placeholderSecrets[c.JWTSecret] {
+		return fmt.Errorf("JWT_SECRET is set to a placeholder value, set a strong secret or APP_ENV=development")
+	}
+	if len(c.JWTSecret) < 32 {
+		return fmt.Errorf("JWT_SECRET must be at least 32 characters outside development")
 	}
+	return nil
 }
 
 // getEnv 获取环境变量，如果不存在则返回默认值
@@ -7,6 +7,21 @@ const (
 	PostStatusActive  = "active"  // 在售
 	PostStatusSold    = "sold"    // 已售出
 	PostStatusDeleted = "deleted" // 已删除
+	PostStatusHidden  = "hidden"  // 已被管理员隐藏（只有卖家本人可以看到）
+)
+
+// ========================================
+// 商品分类常量
+// ========================================
+const (
+	PostCategoryElectronics = "electronics" // 电子产品
+	PostCategoryFurniture   = "furniture"   // 家具
+	PostCategoryClothing    = "clothing"    // 服装
+	PostCategoryBooks       = "books"       // 图书
+	PostCategorySports      = "sports"      // 运动户外
+	PostCategoryHome        = "home"        // 家居日用
+	PostCategoryVehicles    = "vehicles"    // 车辆及配件
+	PostCategoryOther       = "other"       // 其他（未选择分类时的默认值）
 )
 
 // ========================================
@@ -17,6 +32,17 @@ const (
 	RoleAdmin = "admin" // 管理员
 )
 
+// ========================================
+// 权限常量（角色拥有的权限见 service.rolePermissions）
+// ========================================
+const (
+	PermissionManageAnyPost = "posts:manage_any" // 编辑、删除、修改任意商品
+	PermissionManageUsers   = "users:manage"     // 修改用户角色
+	PermissionModerate      = "reports:moderate" // 处理举报：隐藏商品、封禁用户
+	PermissionViewAuditLog  = "audit:read"       // 查看审计日志
+	PermissionViewStats     = "stats:read"       // 查看运营统计
+)
+
 // ========================================
 // 响应状态常量
 // ========================================
@@ -44,6 +70,74 @@ const (
 	MaxPrice = 999999 // 最高价格
 )
 
+// ========================================
+// 交易与评价常量
+// ========================================
+const (
+	ReviewRoleBuyer  = "buyer"  // 买家对卖家的评价
+	ReviewRoleSeller = "seller" // 卖家对买家的评价
+
+	MinRating              = 1    // 最低评分
+	MaxRating              = 5    // 最高评分
+	MaxReviewCommentLength = 1000 // 评价内容最大长度
+	ReviewWindowDays       = 14   // 交易完成后可评价的天数，到期后评价自动公开
+)
+
+// ========================================
+// 通知类型常量
+// ========================================
+const (
+	NotificationTypeNewMessage    = "new_message"    // 收到新消息（预留：消息功能尚未实现，目前没有发送方）
+	NotificationTypeOfferReceived = "offer_received" // 收到出价（预留：出价功能尚未实现，目前没有发送方）
+	NotificationTypeItemSold      = "item_sold"      // 商品已售出（收藏者、买家）
+	NotificationTypeReviewLeft    = "review_left"    // 收到新评价
+	NotificationTypePriceDrop     = "price_drop"     // 收藏的商品降价
+)
+
+// ========================================
+// 邮件发件箱常量
+// ========================================
+const (
+	EmailStatusPending = "pending" // 待发送
+	EmailStatusSent    = "sent"    // 已发送
+	EmailStatusFailed  = "failed"  // 重试次数用尽，发送失败
+
+	EmailMaxAttempts           = 5   // 最多尝试发送次数
+	EmailRetryBaseSeconds      = 30  // 重试间隔基数（指数退避）
+	EmailWorkerIntervalSeconds = 5   // 发件箱轮询间隔
+	EmailWorkerBatchSize       = 10  // 每次轮询最多发送的邮件数
+	EmailLeaseSeconds          = 600 // 领取邮件后的租约时长，需大于一批邮件的最长发送时间
+)
+
+// ========================================
+// 一次性令牌常量
+// ========================================
+const (
+	TokenPurposeEmailVerification = "email_verification" // 邮箱验证
+	TokenPurposePasswordReset     = "password_reset"     // 密码重置
+	TokenPurposeEmailChange       = "email_change"       // 修改邮箱（验证新邮箱）
+
+	EmailVerificationExpiryHours = 24 // 邮箱验证链接有效期（小时）
+	PasswordResetExpiryMinutes   = 30 // 密码重置链接有效期（分钟）
+	EmailChangeExpiryHours       = 24 // 修改邮箱的验证链接有效期（小时）
+	TokenResendCooldownSeconds   = 60 // 重新发送邮件的最短间隔
+)
+
+// ========================================
+// 登录保护常量
+// ========================================
+const (
+	LoginLockoutScopeEmail = "email" // 按邮箱锁定
+	LoginLockoutScopeIP    = "ip"    // 按IP锁定
+
+	LoginFailureWindowMinutes = 15 // 统计失败次数的时间窗口
+	LoginBackoffThreshold     = 3  // 同一邮箱失败超过该次数后开始指数退避
+	LoginBackoffMaxSeconds    = 60 // 退避等待的最长时间
+	LoginEmailLockoutFailures = 10 // 同一邮箱失败达到该次数后锁定
+	LoginIPLockoutFailures    = 50 // 同一IP失败达到该次数后锁定
+	LoginLockoutMinutes       = 15 // 锁定时长
+)
+
 // ========================================
 // 错误消息常量
 // ========================================
@@ -54,11 +148,14 @@ const (
 	ErrInvalidEmail      = "Invalid email format"
 	ErrInvalidPassword   = "Invalid password"
 	ErrPasswordTooShort  = "Password must be at least 6 characters"
+	ErrPasswordTooLong   = "Password is too long"
+	ErrEmailNotVerified  = "Please verify your email address first"
 	
 	// 认证相关错误
 	ErrUnauthorized     = "Unauthorized access"
 	ErrInvalidToken     = "Invalid or expired token"
 	ErrMissingAuthToken = "Missing authorization token"
+	ErrAccountSuspended = "Your account has been suspended"
 	
 	// 商品相关错误
 	ErrPostNotFound   = "Post not found"
@@ -78,6 +175,7 @@ const (
 const (
 	HeaderAuthorization = "Authorization"
 	HeaderContentType   = "Content-Type"
+	HeaderRequestID     = "X-Request-ID"
 )
 
 // ========================================
@@ -93,5 +191,173 @@ const (
 // ========================================
 const (
 	TokenPrefix = "Bearer "
-	TokenExpiry = 24 // 小时
+
+	AccessTokenExpiryMinutes     = 15  // 访问令牌有效期（分钟）
+	RefreshTokenExpiryDays       = 30  // 刷新令牌及会话有效期（天）
+	SessionLastSeenUpdateMinutes = 5   // 会话最近活动时间的更新间隔（分钟）
+	MaxUserAgentLength           = 255 // 会话保存的 User-Agent 最大长度
+	ReauthMaxAgeMinutes          = 10  // 第三方登录用户不输入密码时，会话须在该时间内登录（分钟）
+
+	TokenPurposeTwoFactorChallenge  = "2fa_challenge" // 两步验证挑战令牌
+	TwoFactorChallengeExpiryMinutes = 5               // 挑战令牌有效期（分钟）
+)
+
+// ========================================
+// 两步验证常量
+// ========================================
+const (
+	TOTPIssuer      = "SecondHand" // 验证器应用中显示的服务名称
+	BackupCodeCount = 10           // 每次生成的备用码数量
+)
+
+// ========================================
+// 第三方登录（OIDC）常量
+// ========================================
+const (
+	OIDCStateExpiryMinutes = 10           // 发起登录到回调的最长时间（分钟）
+	OIDCStateCookieName    = "oidc_state" // 绑定浏览器的 state Cookie（保存 state 的哈希）
+)
+
+// ========================================
+// JWT 签名密钥轮换常量
+// ========================================
+const (
+	SigningKeyRotationDays   = 30 // 签名密钥使用多少天后轮换
+	SigningKeyGraceMinutes   = 60 // 轮换后旧密钥继续用于验证的时间（分钟），需大于访问令牌有效期
+	SigningKeyRefreshMinutes = 5  // 重新加载密钥的间隔（分钟）
+
+	SigningKeyReloadMinSeconds = 10 // 遇到未知 kid 时立即重新加载密钥的最小间隔（秒），避免伪造的 kid 频繁查询数据库
+)
+
+// ========================================
+// 个人访问令牌常量
+// ========================================
+const (
+	PersonalAccessTokenPrefix = "shp_" // 令牌前缀，用于区分个人访问令牌和 JWT
+
+	ScopePostsRead         = "posts:read"         // 浏览商品、我的商品
+	ScopePostsWrite        = "posts:write"        // 发布、编辑、删除商品
+	ScopeFavoritesRead     = "favorites:read"     // 查看收藏
+	ScopeFavoritesWrite    = "favorites:write"    // 收藏、取消收藏
+	ScopeNotificationsRead = "notifications:read" // 查看通知
+
+	MaxPersonalAccessTokens            = 20  // 每个用户最多的令牌数量
+	PersonalAccessTokenDefaultDays     = 90  // 默认有效期（天）
+	PersonalAccessTokenMaxDays         = 365 // 最长有效期（天）
+	PersonalAccessTokenLastUsedMinutes = 5   // 最近使用时间的更新间隔（分钟），避免每个请求都写数据库
+)
+
+// ========================================
+// 个人资料常量
+// ========================================
+const (
+	ContactMethodEmail = "email" // 首选联系方式：邮件
+	ContactMethodPhone = "phone" // 首选联系方式：电话
+	ContactMethodText  = "text"  // 首选联系方式：短信
+
+	MaxDisplayNameLength       = 50  // 昵称最大长度
+	MaxBioLength               = 500 // 个人简介最大长度
+	MinUsernameLength          = 3   // 用户名最小长度
+	MaxUsernameLength          = 50  // 用户名最大长度
+	UsernameChangeCooldownDays = 30  // 修改用户名的最短间隔（天）
+)
+
+// ========================================
+// 联系方式查看常量
+// ========================================
+const (
+	MaxContactRevealsPerDay = 20 // 每个用户 24 小时内最多查看的新商品联系方式数量
+)
+
+// ========================================
+// 账号删除常量
+// ========================================
+const (
+	AccountDeletionCoolingOffDays        = 14 // 申请删除账号后的冷静期（天），期间可以取消
+	AccountDeletionWorkerIntervalMinutes = 60 // 检查到期删除任务的间隔
+	AccountDeletionBatchSize             = 20 // 每次最多处理的账号数
+)
+
+// ========================================
+// 关注动态常量
+// ========================================
+const (
+	FeedDefaultLimit  = 20 // 动态每页默认数量
+	FeedMaxLimit      = 50 // 动态每页最大数量
+	FeedBackfillPosts = 20 // 关注卖家时加入动态的已有在售商品数量（标记为已读）
+)
+
+// ========================================
+// 举报与审核常量
+// ========================================
+const (
+	ReportReasonScam        = "scam"        // 诈骗
+	ReportReasonProhibited  = "prohibited"  // 违禁物品
+	ReportReasonCounterfeit = "counterfeit" // 假冒商品
+	ReportReasonSpam        = "spam"        // 垃圾信息
+	ReportReasonOffensive   = "offensive"   // 冒犯性内容
+	ReportReasonOther       = "other"       // 其它
+
+	ReportStatusPending   = "pending"   // 待处理
+	ReportStatusDismissed = "dismissed" // 已驳回
+	ReportStatusActioned  = "actioned"  // 已处理（隐藏商品或封禁用户）
+
+	ModerationActionDismiss  = "dismiss"   // 驳回举报
+	ModerationActionHidePost = "hide_post" // 隐藏商品
+	ModerationActionBanUser  = "ban_user"  // 封禁卖家（同时隐藏其所有在售商品）
+
+	HiddenReasonModeration = "moderation" // 商品被管理员隐藏
+	HiddenReasonSuspension = "suspension" // 卖家被封禁，商品随之隐藏
+
+	MaxReportDetailsLength = 1000 // 举报说明最大长度
+
+	MaxSuspensionReasonLength       = 500 // 封禁原因最大长度
+	SuspensionWorkerIntervalMinutes = 5   // 检查到期临时封禁的间隔
+)
+
+
+// ========================================
+// 审计日志常量
+// ========================================
+const (
+	AuditTargetUser    = "user"    // 操作对象：用户
+	AuditTargetPost    = "post"    // 操作对象：商品
+	AuditTargetSession = "session" // 操作对象：登录会话
+	AuditTargetEmail   = "email"   // 操作对象：邮箱（目标为邮箱哈希）
+	AuditTargetIP      = "ip"      // 操作对象：IP地址
+
+	AuditActionUserRoleChange   = "user.role_change"     // 修改用户角色
+	AuditActionUserSuspend      = "user.suspend"         // 封禁用户
+	AuditActionUserUnsuspend    = "user.unsuspend"       // 解除封禁（包括临时封禁到期）
+	AuditActionPostStatusChange = "post.status_change"   // 修改商品状态
+	AuditActionPostDelete       = "post.delete"          // 删除商品
+	AuditActionReportDecision   = "report.decision"      // 处理举报
+	AuditActionLogin            = "auth.login"           // 登录成功
+	AuditActionLoginFailed      = "auth.login_failed"    // 登录失败
+	AuditActionLogout           = "auth.logout"          // 退出当前会话
+	AuditActionLogoutAll        = "auth.logout_all"      // 退出所有会话
+	AuditActionSessionRevoke    = "auth.session_revoke"  // 注销指定会话
+	AuditActionPasswordChange   = "auth.password_change" // 修改密码
+	AuditActionPasswordReset    = "auth.password_reset"  // 通过邮件重置密码
+	AuditActionTwoFactorEnable  = "auth.2fa_enable"      // 开启两步验证
+	AuditActionTwoFactorDisable = "auth.2fa_disable"     // 关闭两步验证
+
+	AuditActionEmailChangeRequest    = "auth.email_change_request" // 申请修改邮箱（向新邮箱发送验证邮件）
+	AuditActionEmailChange           = "auth.email_change"         // 确认修改邮箱
+	AuditActionTokenCreate           = "auth.token_create"         // 创建个人访问令牌
+	AuditActionTokenRevoke           = "auth.token_revoke"         // 删除（撤销）个人访问令牌
+	AuditActionAccountDeletion       = "user.deletion_request"     // 申请删除账号
+	AuditActionAccountDeletionCancel = "user.deletion_cancel"      // 取消删除账号
+	AuditActionLoginLockout          = "auth.login_lockout"        // 登录失败次数过多，锁定邮箱或IP
+
+	MaxRequestIDLength = 64 // 客户端传入的请求ID最大长度，超过时重新生成
+)
+
+// ========================================
+// 运营统计常量
+// ========================================
+const (
+	StatsDefaultRangeDays = 30  // 未指定日期范围时统计最近多少天
+	StatsMaxRangeDays     = 366 // 日期范围最多多少天
+	StatsCacheTTLSeconds  = 300 // 统计结果缓存时间
 )
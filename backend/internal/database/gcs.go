@@ -8,6 +8,7 @@ import (
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"backend/internal/config"
@@ -97,3 +98,24 @@ func DeleteFile(filename string) error {
 
 	return nil
 }
+
+// OpenFile 读取 GCS 中的文件，调用方负责关闭
+func OpenFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	bucket := gcsClient.Bucket(config.AppConfig.GCSBucket)
+
+	reader, err := bucket.Object(filename).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	return reader, nil
+}
+
+// FileNameFromURL 从 UploadFile 返回的公开 URL 中解析出文件名，不是本 bucket 的文件时返回 false
+func FileNameFromURL(url string) (string, bool) {
+	prefix := fmt.Sprintf("https://storage.googleapis.com/%s/", config.AppConfig.GCSBucket)
+	if !strings.HasPrefix(url, prefix) || len(url) == len(prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(url, prefix), true
+}
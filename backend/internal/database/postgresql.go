@@ -36,15 +36,76 @@ func InitPostgreSQL() error {
 	log.Println("✅ Connected to PostgreSQL database")
 
 	// 3. 自动迁移数据库表（根据模型创建表）
-	if err := db.AutoMigrate(&models.User{}, &models.Post{}); err != nil {
+	// 引入邮箱验证之前注册的用户默认视为已验证，否则会因为新增的 email_verified 列（默认 false）无法发布商品
+	backfillEmailVerified := db.Migrator().HasTable(&models.User{}) &&
+		!db.Migrator().HasColumn(&models.User{}, "EmailVerified")
+	if err := db.AutoMigrate(
+		&models.User{},
+		&models.Post{},
+		&models.Transaction{},
+		&models.Review{},
+		&models.Favorite{},
+		&models.PriceHistory{},
+		&models.Notification{},
+		&models.NotificationPreference{},
+		&models.EmailOutbox{},
+		&models.Session{},
+		&models.RefreshToken{},
+		&models.UserToken{},
+		&models.LoginAttempt{},
+		&models.LoginLockout{},
+		&models.BackupCode{},
+		&models.UserIdentity{},
+		&models.OIDCLoginState{},
+		&models.SigningKey{},
+		&models.PersonalAccessToken{},
+		&models.ContactReveal{},
+		&models.UserBlock{},
+		&models.Follow{},
+		&models.FeedItem{},
+		&models.Report{},
+		&models.ModerationDecision{},
+		&models.AuditLog{},
+	); err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	// 一次性回填：只在本次迁移新增 email_verified 列时执行
+	if backfillEmailVerified {
+		result := db.Model(&models.User{}).Where("email_verified = ?", false).Update("email_verified", true)
+		if result.Error != nil {
+			return fmt.Errorf("failed to backfill email_verified: %w", result.Error)
+		}
+		log.Printf("✅ Marked %d existing users as email verified", result.RowsAffected)
+	}
+
+	// 4. 审计日志只能追加：禁止修改、删除和清空
+	if err := db.Exec(auditLogTriggerSQL).Error; err != nil {
+		return fmt.Errorf("failed to create audit log trigger: %w", err)
+	}
+
 	log.Println("✅ Database migration completed")
 
 	return nil
 }
 
+// auditLogTriggerSQL 阻止对 audit_logs 表执行 UPDATE、DELETE 和 TRUNCATE
+const auditLogTriggerSQL = `
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_no_modify ON audit_logs;
+CREATE TRIGGER audit_logs_no_modify BEFORE UPDATE OR DELETE ON audit_logs
+	FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
+
+DROP TRIGGER IF EXISTS audit_logs_no_truncate ON audit_logs;
+CREATE TRIGGER audit_logs_no_truncate BEFORE TRUNCATE ON audit_logs
+	FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only();
+`
+
 // GetDB 获取数据库连接实例
 func GetDB() *gorm.DB {
 	return db
@@ -0,0 +1,73 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// 模板文件：每个邮件包含 <name>.txt（纯文本正文，并用 {{define "<name>.subject"}} 定义主题）
+// 和 <name>.html（HTML 正文）
+//
+//go:embed templates/*.txt templates/*.html
+var templateFS embed.FS
+
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html"))
+)
+
+// 邮件模板名称
+const (
+	TemplateVerifyEmail     = "verify_email"     // 邮箱验证
+	TemplatePasswordReset   = "password_reset"   // 密码重置
+	TemplateEmailChange     = "email_change"     // 修改邮箱（验证新邮箱）
+	TemplateEmailChanged    = "email_changed"    // 修改邮箱完成（通知原邮箱）
+	TemplateNotification    = "notification"     // 站内通知的邮件副本
+	TemplateAccountDeletion = "account_deletion" // 账号计划删除提醒
+)
+
+// Message 渲染好的邮件内容
+type Message struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// Render 使用模板渲染邮件
+func Render(name string, to string, data interface{}) (*Message, error) {
+	textTmpl := textTemplates.Lookup(name + ".txt")
+	htmlTmpl := htmlTemplates.Lookup(name + ".html")
+	if textTmpl == nil || htmlTmpl == nil {
+		return nil, fmt.Errorf("email template not found: %s", name)
+	}
+
+	// 1. 渲染主题
+	var subject bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&subject, name+".subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render subject: %w", err)
+	}
+
+	// 2. 渲染纯文本正文
+	var text bytes.Buffer
+	if err := textTmpl.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to render text body: %w", err)
+	}
+
+	// 3. 渲染 HTML 正文
+	var html bytes.Buffer
+	if err := htmlTmpl.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("failed to render html body: %w", err)
+	}
+
+	return &Message{
+		To:       to,
+		Subject:  strings.TrimSpace(subject.String()),
+		TextBody: strings.TrimSpace(text.String()) + "\n",
+		HTMLBody: html.String(),
+	}, nil
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"backend/internal/constants"
+	"backend/internal/models"
+	"backend/internal/service"
+	"backend/pkg/utils"
+)
+
+// exportAccountHandler 下载我的个人数据（ZIP）
+// GET /me/export
+func exportAccountHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 调用 service 层查询数据
+	export, err := service.GetAccountExport(userID)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to export account: "+err.Error())
+		return
+	}
+
+	// 3. 以附件形式返回 ZIP（开始写入后无法再返回错误响应，只记录日志）
+	filename := fmt.Sprintf("secondhand-export-%s.zip", time.Now().Format("20060102"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	if err := export.WriteZip(r.Context(), w); err != nil {
+		log.Printf("⚠️  Failed to write account export for user %d: %v", userID, err)
+	}
+}
+
+// deleteAccountHandler 申请删除账号（冷静期结束后才会删除，期间可以取消）
+// DELETE /me
+func deleteAccountHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析请求体（第三方登录的用户刚登录时可以不提供密码）
+	var req struct {
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	sessionID, _ := r.Context().Value("sessionID").(string)
+
+	// 3. 调用 service 层申请删除
+	user, err := service.RequestAccountDeletion(userID, sessionID, req.Password)
+	if err != nil {
+		switch err.Error() {
+		case "current password is incorrect":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Password is incorrect")
+		case "recent login required":
+			utils.SendErrorResponse(w, http.StatusForbidden, "Please log in again to confirm this action")
+		case "account deletion already scheduled":
+			utils.SendErrorResponse(w, http.StatusConflict, "Account deletion is already scheduled")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to delete account: "+err.Error())
+		}
+		return
+	}
+
+	// 4. 记录审计日志并返回成功响应
+	recordAuthEvent(r, userID, constants.AuditActionAccountDeletion, models.AuditState{"deletion_scheduled_at": user.DeletionScheduledAt})
+	utils.SendSuccessWithMessage(w, "Account deletion scheduled", map[string]interface{}{
+		"deletion_scheduled_at": user.DeletionScheduledAt,
+	})
+}
+
+// cancelAccountDeletionHandler 取消删除账号
+// DELETE /me/deletion
+func cancelAccountDeletionHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 调用 service 层取消删除
+	if err := service.CancelAccountDeletion(userID); err != nil {
+		if err.Error() == "account deletion not scheduled" {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Account deletion is not scheduled")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to cancel account deletion: "+err.Error())
+		return
+	}
+
+	// 3. 记录审计日志并返回成功响应
+	recordAuthEvent(r, userID, constants.AuditActionAccountDeletionCancel, nil)
+	utils.SendSuccessWithMessage(w, "Account deletion cancelled", nil)
+}
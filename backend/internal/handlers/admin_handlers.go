@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"backend/internal/constants"
+	"backend/internal/service"
+	"backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// updateUserRoleHandler 修改用户角色（管理员）
+// PUT /admin/users/{id}/role
+func updateUserRoleHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取用户ID
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 3. 解析请求体
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Role == "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Role is required")
+		return
+	}
+
+	// 4. 调用 service 层修改角色
+	user, err := service.UpdateUserRole(actor, userID, req.Role)
+	if err != nil {
+		switch err.Error() {
+		case "record not found":
+			utils.SendErrorResponse(w, http.StatusNotFound, "User not found")
+		case "invalid role":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid role")
+		case "unauthorized: you cannot manage users":
+			utils.SendErrorResponse(w, http.StatusForbidden, "You do not have permission to manage users")
+		case "cannot remove the last admin":
+			utils.SendErrorResponse(w, http.StatusConflict, "Cannot remove the last admin")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to update role: "+err.Error())
+		}
+		return
+	}
+
+	// 5. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Role updated successfully", user)
+}
+
+// suspendUserHandler 封禁用户（管理员）
+// POST /admin/users/{id}/suspend
+// until 为空表示永久封禁，否则为 RFC3339 格式的解封时间
+func suspendUserHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取用户ID
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 3. 解析请求体
+	var req struct {
+		Reason string `json:"reason"`
+		Until  string `json:"until"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Reason is required")
+		return
+	}
+	if utf8.RuneCountInString(req.Reason) > constants.MaxSuspensionReasonLength {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Reason is too long")
+		return
+	}
+	var until *time.Time
+	if req.Until != "" {
+		t, err := time.Parse(time.RFC3339, req.Until)
+		if err != nil {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid until time, expected RFC3339 format")
+			return
+		}
+		until = &t
+	}
+
+	// 4. 调用 service 层封禁用户
+	user, err := service.SuspendUser(actor, userID, req.Reason, until)
+	if err != nil {
+		switch err.Error() {
+		case "record not found":
+			utils.SendErrorResponse(w, http.StatusNotFound, "User not found")
+		case "unauthorized: you cannot manage users":
+			utils.SendErrorResponse(w, http.StatusForbidden, "You do not have permission to manage users")
+		case "you cannot suspend yourself", "cannot suspend an admin":
+			utils.SendErrorResponse(w, http.StatusConflict, "Cannot suspend this user")
+		case "suspension end must be in the future":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Until time must be in the future")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to suspend user: "+err.Error())
+		}
+		return
+	}
+
+	// 5. 返回成功响应
+	utils.SendSuccessWithMessage(w, "User suspended successfully", user)
+}
+
+// liftSuspensionHandler 解除封禁（管理员）
+// DELETE /admin/users/{id}/suspend
+func liftSuspensionHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取用户ID
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 3. 调用 service 层解除封禁
+	user, err := service.LiftSuspension(actor, userID)
+	if err != nil {
+		switch err.Error() {
+		case "record not found":
+			utils.SendErrorResponse(w, http.StatusNotFound, "User not found")
+		case "unauthorized: you cannot manage users":
+			utils.SendErrorResponse(w, http.StatusForbidden, "You do not have permission to manage users")
+		case "user is not suspended":
+			utils.SendErrorResponse(w, http.StatusConflict, "User is not suspended")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to lift suspension: "+err.Error())
+		}
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Suspension lifted successfully", user)
+}
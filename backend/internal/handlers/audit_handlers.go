@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/internal/service"
+	"backend/pkg/utils"
+)
+
+// getAuditLogsHandler 查询审计日志（管理员）
+// GET /admin/audit-logs?actor_id=&action=&target_type=&target_id=&request_id=&from=&to=&page=1&page_size=50
+func getAuditLogsHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析筛选条件和分页参数
+	query := r.URL.Query()
+	actorID := 0
+	if v := query.Get("actor_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid actor ID")
+			return
+		}
+		actorID = id
+	}
+	from, err := parseTimeParam(r, "from")
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid from time, expected RFC3339 or YYYY-MM-DD")
+		return
+	}
+	to, err := parseTimeParam(r, "to")
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid to time, expected RFC3339 or YYYY-MM-DD")
+		return
+	}
+	page, pageSize := parsePagination(r, 50)
+
+	// 3. 调用 service 层查询
+	resp, err := service.GetAuditLogs(service.GetAuditLogsRequest{
+		Actor:      actor,
+		ActorID:    actorID,
+		Action:     query.Get("action"),
+		TargetType: query.Get("target_type"),
+		TargetID:   query.Get("target_id"),
+		RequestID:  query.Get("request_id"),
+		From:       from,
+		To:         to,
+		Page:       page,
+		PageSize:   pageSize,
+	})
+	if err != nil {
+		if err.Error() == "unauthorized: you cannot view audit logs" {
+			utils.SendErrorResponse(w, http.StatusForbidden, "You do not have permission to view audit logs")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get audit logs: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessResponse(w, resp)
+}
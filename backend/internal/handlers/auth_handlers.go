@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"backend/internal/constants"
+	"backend/internal/models"
+	"backend/internal/service"
+	"backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// refreshTokenHandler 使用刷新令牌换取新的访问令牌（刷新令牌同时轮换）
+// POST /refresh
+func refreshTokenHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 解析请求体
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.RefreshToken == "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Refresh token is required")
+		return
+	}
+
+	// 2. 调用 service 层轮换令牌
+	tokens, err := service.RefreshTokens(req.RefreshToken)
+	if err != nil {
+		if err.Error() == "invalid refresh token" || err.Error() == "refresh token reuse detected" {
+			utils.SendErrorResponse(w, http.StatusUnauthorized, "Invalid or expired refresh token")
+			return
+		}
+		if err.Error() == "account suspended" {
+			utils.SendErrorResponse(w, http.StatusForbidden, constants.ErrAccountSuspended)
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to refresh token: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	utils.SendSuccessResponse(w, tokens)
+}
+
+// logoutHandler 退出当前会话
+// POST /logout
+func logoutHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID和会话ID
+	userID, ok := r.Context().Value("userID").(int)
+	sessionID, _ := r.Context().Value("sessionID").(string)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 注销会话（访问令牌和刷新令牌都会失效）
+	if err := service.RevokeSession(userID, sessionID); err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to log out: "+err.Error())
+		return
+	}
+
+	// 3. 记录审计日志并返回成功响应
+	recordAuthEvent(r, userID, constants.AuditActionLogout, models.AuditState{"session_id": sessionID})
+	utils.SendSuccessWithMessage(w, "Logged out successfully", nil)
+}
+
+// logoutAllHandler 退出所有设备上的会话
+// POST /logout-all
+func logoutAllHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 注销所有会话
+	if err := service.RevokeAllSessions(userID); err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to log out: "+err.Error())
+		return
+	}
+
+	// 3. 记录审计日志并返回成功响应
+	recordAuthEvent(r, userID, constants.AuditActionLogoutAll, nil)
+	utils.SendSuccessWithMessage(w, "Logged out from all sessions", nil)
+}
+
+// getSessionsHandler 获取我当前登录的所有会话（设备）
+// GET /sessions
+func getSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID和会话ID
+	userID, ok := r.Context().Value("userID").(int)
+	sessionID, _ := r.Context().Value("sessionID").(string)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 调用 service 层查询
+	sessions, err := service.GetActiveSessions(userID, sessionID)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get sessions: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	utils.SendSuccessResponse(w, sessions)
+}
+
+// revokeSessionHandler 注销指定会话（在其他设备上退出登录）
+// DELETE /sessions/{id}
+func revokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 调用 service 层注销会话
+	if err := service.RevokeActiveSession(userID, mux.Vars(r)["id"]); err != nil {
+		if err.Error() == "record not found" {
+			utils.SendErrorResponse(w, http.StatusNotFound, "Session not found")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to revoke session: "+err.Error())
+		return
+	}
+
+	// 3. 记录审计日志并返回成功响应
+	recordAuthEvent(r, userID, constants.AuditActionSessionRevoke, models.AuditState{"session_id": mux.Vars(r)["id"]})
+	utils.SendSuccessWithMessage(w, "Session revoked successfully", nil)
+}
+
+// verifyEmailHandler 使用邮件中的令牌验证邮箱
+// POST /verify-email
+func verifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 解析请求体
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Verification token is required")
+		return
+	}
+
+	// 2. 调用 service 层验证邮箱
+	user, err := service.VerifyEmail(req.Token)
+	if err != nil {
+		if err.Error() == "invalid or expired token" {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid or expired verification link")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to verify email: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Email verified successfully", user)
+}
+
+// resendVerificationHandler 重新发送邮箱验证邮件
+// POST /verify-email/resend
+func resendVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 调用 service 层重新发送
+	if err := service.ResendVerificationEmail(userID); err != nil {
+		if err.Error() == "email already verified" {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Email is already verified")
+			return
+		}
+		if err.Error() == "please wait before requesting another email" {
+			utils.SendErrorResponse(w, http.StatusTooManyRequests, "Please wait before requesting another email")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to send verification email: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Verification email sent", nil)
+}
+
+// forgotPasswordHandler 发送密码重置邮件
+// POST /forgot-password
+func forgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 解析请求体
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Email == "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Email is required")
+		return
+	}
+
+	// 2. 调用 service 层发送重置邮件
+	if err := service.RequestPasswordReset(req.Email); err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to request password reset: "+err.Error())
+		return
+	}
+
+	// 3. 无论邮箱是否存在都返回相同的响应
+	utils.SendSuccessWithMessage(w, "If an account exists for this email, a password reset link has been sent", nil)
+}
+
+// resetPasswordHandler 使用重置令牌设置新密码
+// POST /reset-password
+func resetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 解析请求体
+	var req struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Token == "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Reset token is required")
+		return
+	}
+
+	// 2. 验证密码长度
+	if msg := validatePassword(req.Password); msg != "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	// 3. 调用 service 层重置密码
+	userID, err := service.ResetPassword(req.Token, req.Password)
+	if err != nil {
+		if err.Error() == "invalid or expired token" {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid or expired reset link")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to reset password: "+err.Error())
+		return
+	}
+
+	// 4. 记录审计日志并返回成功响应
+	recordAuthEvent(r, userID, constants.AuditActionPasswordReset, nil)
+	utils.SendSuccessWithMessage(w, "Password has been reset, please log in again", nil)
+}
+
+// changePasswordHandler 修改密码（其他设备上的会话会被注销）
+// POST /change-password
+func changePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID和会话ID
+	userID, ok := r.Context().Value("userID").(int)
+	sessionID, _ := r.Context().Value("sessionID").(string)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析请求体
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.CurrentPassword == "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Current password is required")
+		return
+	}
+
+	// 3. 验证新密码长度
+	if msg := validatePassword(req.NewPassword); msg != "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	// 4. 当前密码的失败次数与登录共用限制
+	email, ok := checkPasswordAttempt(w, r, userID)
+	if !ok {
+		return
+	}
+
+	// 5. 调用 service 层修改密码
+	err := service.ChangePassword(service.ChangePasswordRequest{
+		UserID:          userID,
+		SessionID:       sessionID,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	})
+	if err != nil {
+		if err.Error() == "current password is incorrect" {
+			recordLoginAttempt(r, email, false)
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Current password is incorrect")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to change password: "+err.Error())
+		return
+	}
+
+	// 6. 记录审计日志并返回成功响应
+	recordLoginAttempt(r, email, true)
+	recordAuthEvent(r, userID, constants.AuditActionPasswordChange, nil)
+	utils.SendSuccessWithMessage(w, "Password changed successfully", nil)
+}
+
+// jwksHandler 公开 JWT 签名公钥（包括轮换后仍在有效期内的旧密钥）
+// GET /.well-known/jwks.json
+func jwksHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(utils.PublicJWKS())
+}
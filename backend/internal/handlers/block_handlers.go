@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"backend/internal/constants"
+	"backend/internal/service"
+	"backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// getBlockedUsersHandler 获取我屏蔽的用户
+// GET /blocks?page=1&page_size=20
+func getBlockedUsersHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 获取分页参数
+	page, pageSize := parsePagination(r, 20)
+
+	// 3. 调用 service 层获取数据
+	resp, err := service.GetBlockedUsers(service.GetBlockedUsersRequest{
+		UserID:   userID,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get blocked users: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessResponse(w, resp)
+}
+
+// blockUserHandler 屏蔽用户
+// POST /blocks
+func blockUserHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析请求体
+	var req struct {
+		UserID int `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID <= 0 {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "user_id is required")
+		return
+	}
+
+	// 3. 调用 service 层屏蔽用户
+	if err := service.BlockUser(userID, req.UserID); err != nil {
+		if err.Error() == "record not found" {
+			utils.SendErrorResponse(w, http.StatusNotFound, constants.ErrUserNotFound)
+			return
+		}
+		if err.Error() == "you cannot block yourself" {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "You cannot block yourself")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to block user: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessWithMessage(w, "User blocked", nil)
+}
+
+// unblockUserHandler 取消屏蔽
+// DELETE /blocks/{id}
+func unblockUserHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取被屏蔽的用户ID
+	blockedID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 3. 调用 service 层取消屏蔽
+	if err := service.UnblockUser(userID, blockedID); err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to unblock user: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessWithMessage(w, "User unblocked", nil)
+}
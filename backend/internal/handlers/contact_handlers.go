@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/internal/service"
+	"backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// revealContactHandler 查看商品的完整联系方式（会记录查看者，并限制每日查看次数）
+// POST /item/{id}/contact
+func revealContactHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取商品ID
+	postID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid post ID")
+		return
+	}
+
+	// 3. 调用 service 层查看联系方式
+	contact, err := service.RevealContactInfo(postID, userID)
+	if err != nil {
+		// 判断错误类型
+		if err.Error() == "record not found" {
+			utils.SendErrorResponse(w, http.StatusNotFound, "Post not found")
+			return
+		}
+		if err.Error() == "unauthorized: the seller has blocked you" {
+			utils.SendErrorResponse(w, http.StatusForbidden, "You cannot contact this seller")
+			return
+		}
+		if err.Error() == "daily contact reveal limit reached" {
+			utils.SendErrorResponse(w, http.StatusTooManyRequests, "You have reached the daily limit for viewing contact info")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get contact info: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessResponse(w, contact)
+}
+
+// getContactRevealsHandler 查看谁查看过我的商品联系方式
+// GET /item/{id}/contact-reveals?page=1&page_size=10
+func getContactRevealsHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取商品ID
+	postID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid post ID")
+		return
+	}
+
+	// 3. 获取分页参数
+	page, pageSize := parsePagination(r, 10)
+
+	// 4. 调用 service 层获取数据
+	resp, err := service.GetContactReveals(service.GetContactRevealsRequest{
+		PostID:   postID,
+		Actor:    actor,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		// 判断错误类型
+		if err.Error() == "record not found" {
+			utils.SendErrorResponse(w, http.StatusNotFound, "Post not found")
+			return
+		}
+		if err.Error() == "unauthorized: you can only view reveals of your own posts" {
+			utils.SendErrorResponse(w, http.StatusForbidden, "You can only view reveals of your own posts")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get contact reveals: "+err.Error())
+		return
+	}
+
+	// 5. 返回成功响应
+	utils.SendSuccessResponse(w, resp)
+}
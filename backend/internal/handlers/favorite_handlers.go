@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/internal/service"
+	"backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// addFavoriteHandler 收藏商品
+// POST /item/{id}/favorite
+func addFavoriteHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取商品ID
+	postID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid post ID")
+		return
+	}
+
+	// 3. 调用 service 层收藏商品
+	if err := service.AddFavorite(userID, postID); err != nil {
+		if err.Error() == "record not found" {
+			utils.SendErrorResponse(w, http.StatusNotFound, "Post not found")
+			return
+		}
+		if err.Error() == "unauthorized: the seller has blocked you" {
+			utils.SendErrorResponse(w, http.StatusForbidden, "You cannot favorite this post")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to favorite post: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Post added to favorites", nil)
+}
+
+// removeFavoriteHandler 取消收藏
+// DELETE /item/{id}/favorite
+func removeFavoriteHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取商品ID
+	postID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid post ID")
+		return
+	}
+
+	// 3. 调用 service 层取消收藏
+	if err := service.RemoveFavorite(userID, postID); err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to unfavorite post: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Post removed from favorites", nil)
+}
+
+// getFavoritesHandler 获取我的收藏列表
+// GET /favorites?page=1&page_size=8
+func getFavoritesHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 获取分页参数
+	page, pageSize := parsePagination(r, 8)
+
+	// 3. 调用 service 层获取数据
+	resp, err := service.GetFavorites(service.GetFavoritesRequest{
+		UserID:   userID,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get favorites: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessResponse(w, resp)
+}
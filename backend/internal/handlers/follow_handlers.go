@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/internal/constants"
+	"backend/internal/service"
+	"backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// followSellerHandler 关注卖家
+// POST /users/{id}/follow
+func followSellerHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取卖家ID
+	sellerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 3. 调用 service 层关注卖家
+	if err := service.FollowSeller(userID, sellerID); err != nil {
+		switch err.Error() {
+		case "record not found":
+			utils.SendErrorResponse(w, http.StatusNotFound, constants.ErrUserNotFound)
+		case "you cannot follow yourself":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "You cannot follow yourself")
+		case "unauthorized: the seller has blocked you":
+			utils.SendErrorResponse(w, http.StatusForbidden, "You cannot follow this seller")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to follow seller: "+err.Error())
+		}
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Seller followed", nil)
+}
+
+// unfollowSellerHandler 取消关注
+// DELETE /users/{id}/follow
+func unfollowSellerHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取卖家ID
+	sellerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 3. 调用 service 层取消关注
+	if err := service.UnfollowSeller(userID, sellerID); err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to unfollow seller: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Seller unfollowed", nil)
+}
+
+// getFollowingHandler 获取我关注的卖家
+// GET /following?page=1&page_size=20
+func getFollowingHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 获取分页参数
+	page, pageSize := parsePagination(r, 20)
+
+	// 3. 调用 service 层获取数据
+	resp, err := service.GetFollowing(service.GetFollowingRequest{
+		UserID:   userID,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get following: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessResponse(w, resp)
+}
+
+// getFeedHandler 获取关注的卖家发布的商品（游标分页）
+// GET /feed?cursor=<next_cursor>&limit=20
+func getFeedHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 获取游标参数
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	// 3. 调用 service 层获取数据
+	resp, err := service.GetFeed(service.GetFeedRequest{
+		UserID: userID,
+		Cursor: r.URL.Query().Get("cursor"),
+		Limit:  limit,
+	})
+	if err != nil {
+		if err.Error() == "invalid cursor" {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get feed: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessResponse(w, resp)
+}
+
+// markFeedReadHandler 将动态全部标记为已读
+// PUT /feed/read-all
+func markFeedReadHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 调用 service 层标记已读
+	if err := service.MarkFeedRead(userID); err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to mark feed as read: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Feed marked as read", nil)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/service"
+	"backend/pkg/utils"
+)
+
+// parsePagination 从查询参数中解析分页参数（page, page_size），非法值使用默认值
+func parsePagination(r *http.Request, defaultPageSize int) (int, int) {
+	page := 1
+	pageSize := defaultPageSize
+
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if ps, err := strconv.Atoi(r.URL.Query().Get("page_size")); err == nil && ps > 0 {
+		pageSize = ps
+	}
+
+	return page, pageSize
+}
+
+// parseTimeParam 解析时间查询参数，支持 RFC3339 和 YYYY-MM-DD（UTC 当天 0 点），参数为空时返回 nil
+func parseTimeParam(r *http.Request, name string) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// actorFromRequest 从 Context 中获取当前操作者（用户ID、角色，以及审计日志需要的IP和请求ID）
+func actorFromRequest(r *http.Request) (service.Actor, bool) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		return service.Actor{}, false
+	}
+	role, _ := r.Context().Value("role").(string)
+	requestID, _ := r.Context().Value("requestID").(string)
+	return service.Actor{UserID: userID, Role: role, IP: utils.ClientIP(r), RequestID: requestID}, true
+}
+
+// sessionClientFromRequest 获取发起登录的客户端信息（用于会话列表展示）
+func sessionClientFromRequest(r *http.Request) service.SessionClient {
+	return service.SessionClient{
+		UserAgent: r.UserAgent(),
+		IP:        utils.ClientIP(r),
+	}
+}
+
+// recordAuthEvent 记录认证事件的审计日志（userID 为 0 表示无法确定用户，如邮箱不存在），记录失败不影响请求
+func recordAuthEvent(r *http.Request, userID int, action string, details models.AuditState) {
+	role, _ := r.Context().Value("role").(string)
+	requestID, _ := r.Context().Value("requestID").(string)
+	actor := service.Actor{UserID: userID, Role: role, IP: utils.ClientIP(r), RequestID: requestID}
+	if err := service.RecordAuthEvent(actor, action, details); err != nil {
+		log.Printf("⚠️  Failed to record audit log %s: %v", action, err)
+	}
+}
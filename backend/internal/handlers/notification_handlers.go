@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/internal/service"
+	"backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// getNotificationsHandler 获取我的通知列表
+// GET /notifications?unread=true&page=1&page_size=20
+func getNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 获取查询参数
+	page, pageSize := parsePagination(r, 20)
+	unread := r.URL.Query().Get("unread")
+
+	// 3. 调用 service 层获取数据
+	resp, err := service.GetNotifications(service.GetNotificationsRequest{
+		UserID:     userID,
+		UnreadOnly: unread == "true" || unread == "1",
+		Page:       page,
+		PageSize:   pageSize,
+	})
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get notifications: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessResponse(w, resp)
+}
+
+// getUnreadNotificationCountHandler 获取未读通知数量
+// GET /notifications/unread-count
+func getUnreadNotificationCountHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 调用 service 层获取未读数量
+	count, err := service.GetUnreadNotificationCount(userID)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get unread count: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	utils.SendSuccessResponse(w, map[string]int64{"unread_count": count})
+}
+
+// markNotificationReadHandler 将一条通知标记为已读
+// PUT /notifications/{id}/read
+func markNotificationReadHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取通知ID
+	notificationID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid notification ID")
+		return
+	}
+
+	// 3. 调用 service 层标记已读
+	if err := service.MarkNotificationRead(userID, notificationID); err != nil {
+		if err.Error() == "record not found" {
+			utils.SendErrorResponse(w, http.StatusNotFound, "Notification not found")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to mark notification as read: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Notification marked as read", nil)
+}
+
+// markAllNotificationsReadHandler 将所有通知标记为已读
+// PUT /notifications/read-all
+func markAllNotificationsReadHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 调用 service 层标记全部已读
+	if err := service.MarkAllNotificationsRead(userID); err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to mark notifications as read: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	utils.SendSuccessWithMessage(w, "All notifications marked as read", nil)
+}
+
+// getNotificationPreferencesHandler 获取通知偏好
+// GET /notifications/preferences
+func getNotificationPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 调用 service 层获取偏好
+	preferences, err := service.GetNotificationPreferences(userID)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get notification preferences: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	utils.SendSuccessResponse(w, preferences)
+}
+
+// updateNotificationPreferencesHandler 更新通知偏好
+// PUT /notifications/preferences
+// 请求体示例：{"price_drop": {"enabled": false}, "review_left": {"email_enabled": false}}
+func updateNotificationPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析请求体
+	var req map[string]service.NotificationSettingUpdate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// 3. 调用 service 层更新偏好
+	preferences, err := service.UpdateNotificationPreferences(userID, req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid notification type") {
+			utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to update notification preferences: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Notification preferences updated", preferences)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"backend/internal/config"
+	"backend/internal/constants"
+	"backend/internal/models"
+	"backend/internal/service"
+	"backend/pkg/utils"
+)
+
+// oidcLoginHandler 发起第三方登录，返回提供方的授权地址
+// GET /auth/oidc/login
+func oidcLoginHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 调用 service 层生成授权地址
+	start, err := service.StartOIDCLogin(r.Context())
+	if err != nil {
+		if err.Error() == "oidc login not configured" {
+			utils.SendErrorResponse(w, http.StatusNotFound, "Single sign-on is not enabled")
+			return
+		}
+		log.Printf("⚠️  Failed to start OIDC login: %v", err)
+		utils.SendErrorResponse(w, http.StatusBadGateway, "Identity provider is unavailable")
+		return
+	}
+
+	// 2. 把 state 绑定到当前浏览器，防止攻击者把自己的登录回调塞给受害者（登录 CSRF）
+	setOIDCStateCookie(w, utils.HashToken(start.State), constants.OIDCStateExpiryMinutes*60)
+
+	// 3. 返回成功响应
+	utils.SendSuccessResponse(w, start)
+}
+
+// oidcCallbackHandler 第三方登录回调：前端把提供方返回的 code 和 state 提交过来
+// POST /auth/oidc/callback
+func oidcCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 解析请求体
+	var req struct {
+		Code  string `json:"code"`
+		State string `json:"state"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Code == "" || req.State == "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Code and state are required")
+		return
+	}
+
+	// 2. state 必须与发起登录时写入本浏览器的 Cookie 一致（无论结果如何都清除 Cookie）
+	setOIDCStateCookie(w, "", -1)
+	cookie, err := r.Cookie(constants.OIDCStateCookieName)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(utils.HashToken(req.State))) != 1 {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Login session expired, please try again")
+		return
+	}
+
+	// 3. 调用 service 层完成登录
+	user, err := service.CompleteOIDCLogin(r.Context(), req.Code, req.State)
+	if err != nil {
+		switch {
+		case err.Error() == "oidc login not configured":
+			utils.SendErrorResponse(w, http.StatusNotFound, "Single sign-on is not enabled")
+		case err.Error() == "invalid or expired state":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Login session expired, please try again")
+		case err.Error() == "verified email required":
+			utils.SendErrorResponse(w, http.StatusForbidden, "Your identity provider did not share a verified email address")
+		case err.Error() == "account email not verified":
+			utils.SendErrorResponse(w, http.StatusConflict, "An account with this email exists but is not verified, please log in with your password and verify your email first")
+		case strings.HasPrefix(err.Error(), "oidc authentication failed"):
+			log.Printf("⚠️  OIDC login failed: %v", err)
+			utils.SendErrorResponse(w, http.StatusUnauthorized, "Single sign-on failed")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to log in: "+err.Error())
+		}
+		return
+	}
+
+	// 4. 被封禁的用户不能登录
+	if user.IsSuspended() {
+		utils.SendErrorResponse(w, http.StatusForbidden, service.SuspensionMessage(user))
+		return
+	}
+
+	// 5. 开启了两步验证：同样需要提交验证码
+	if user.TwoFactorEnabled {
+		challengeToken, err := utils.GenerateChallengeToken(user.ID)
+		if err != nil {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to generate token")
+			return
+		}
+		utils.SendSuccessResponse(w, TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		})
+		return
+	}
+
+	// 6. 创建登录会话并签发令牌
+	recordAuthEvent(r, user.ID, constants.AuditActionLogin, models.AuditState{"method": "oidc"})
+	tokens, err := service.IssueTokens(user.ID, sessionClientFromRequest(r))
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	// 7. 返回响应
+	utils.SendSuccessResponse(w, newAuthResponse(tokens, user))
+}
+
+// setOIDCStateCookie 写入（maxAge < 0 时删除）绑定浏览器的 state Cookie
+// 生产环境经 Nginx 的 /api/ 转发，路径会被改写，因此 Path 使用 "/"
+func setOIDCStateCookie(w http.ResponseWriter, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     constants.OIDCStateCookieName,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   !config.AppConfig.IsDevelopment(),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
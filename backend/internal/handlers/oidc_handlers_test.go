@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend/internal/config"
+	"backend/internal/constants"
+	"backend/pkg/utils"
+)
+
+// newCallbackRequest 构造回调请求，cookie 为空时不带 state Cookie
+func newCallbackRequest(state, cookie string) *http.Request {
+	body := `{"code":"code-1","state":"` + state + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/auth/oidc/callback", strings.NewReader(body))
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: constants.OIDCStateCookieName, Value: cookie})
+	}
+	return req
+}
+
+func TestOIDCCallbackRequiresStateCookie(t *testing.T) {
+	config.AppConfig = &config.Config{AppEnv: "development"}
+
+	tests := []struct {
+		name   string
+		cookie string
+	}{
+		{name: "missing cookie", cookie: ""},
+		{name: "cookie from another login", cookie: utils.HashToken("state-other")},
+		{name: "raw state instead of hash", cookie: "state-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			oidcCallbackHandler(rec, newCallbackRequest("state-1", tt.cookie))
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestOIDCCallbackAcceptsMatchingStateCookie(t *testing.T) {
+	// OIDC 未配置：通过 Cookie 校验后由 service 层返回 404
+	config.AppConfig = &config.Config{AppEnv: "development"}
+
+	rec := httptest.NewRecorder()
+	oidcCallbackHandler(rec, newCallbackRequest("state-1", utils.HashToken("state-1")))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	// state Cookie 只能使用一次
+	cleared := false
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == constants.OIDCStateCookieName && c.MaxAge < 0 {
+			cleared = true
+		}
+	}
+	if !cleared {
+		t.Fatalf("expected the state cookie to be cleared")
+	}
+}
+
+func TestOIDCStateCookieAttributes(t *testing.T) {
+	config.AppConfig = &config.Config{AppEnv: "production"}
+
+	rec := httptest.NewRecorder()
+	setOIDCStateCookie(rec, utils.HashToken("state-1"), constants.OIDCStateExpiryMinutes*60)
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected one cookie, got %d", len(cookies))
+	}
+	c := cookies[0]
+	if !c.HttpOnly || !c.Secure || c.SameSite != http.SameSiteLaxMode {
+		t.Fatalf("cookie must be HttpOnly, Secure and SameSite=Lax: %+v", c)
+	}
+	if c.Value != utils.HashToken("state-1") {
+		t.Fatalf("cookie must hold the state hash, got %q", c.Value)
+	}
+}
@@ -14,6 +14,9 @@ import (
 // getPostsHandler 获取商品列表（支持分页）
 // GET /items?page=1&page_size=8
 func getPostsHandler(w http.ResponseWriter, r *http.Request) {
+	// 从 Context 中获取当前用户ID（用于判断是否已收藏）
+	userID, _ := r.Context().Value("userID").(int)
+
 	// 1. 获取查询参数
 	pageStr := r.URL.Query().Get("page")
 	pageSizeStr := r.URL.Query().Get("page_size")
@@ -36,6 +39,7 @@ func getPostsHandler(w http.ResponseWriter, r *http.Request) {
 
 	// 3. 调用 service 层获取数据
 	resp, err := service.GetPosts(service.GetPostsRequest{
+		ViewerID: userID,
 		Page:     page,
 		PageSize: pageSize,
 	})
@@ -51,6 +55,9 @@ func getPostsHandler(w http.ResponseWriter, r *http.Request) {
 // getPostByIDHandler 根据ID获取商品详情
 // GET /item/{id}
 func getPostByIDHandler(w http.ResponseWriter, r *http.Request) {
+	// 从 Context 中获取当前用户ID（用于判断是否已收藏）
+	userID, _ := r.Context().Value("userID").(int)
+
 	// 1. 从路径参数中获取 ID
 	vars := mux.Vars(r)
 	idStr := vars["id"]
@@ -63,7 +70,7 @@ func getPostByIDHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 3. 调用 service 层获取商品详情
-	post, err := service.GetPostByID(postID)
+	post, err := service.GetPostByID(postID, userID)
 	if err != nil {
 		// 如果找不到商品，返回404
 		utils.SendErrorResponse(w, http.StatusNotFound, "Post not found")
@@ -122,8 +129,8 @@ func myListingsHandler(w http.ResponseWriter, r *http.Request) {
 // deletePostHandler 删除商品（软删除）
 // DELETE /item/{id}
 func deletePostHandler(w http.ResponseWriter, r *http.Request) {
-	// 1. 从 Context 中获取用户ID
-	userID, ok := r.Context().Value("userID").(int)
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
 	if !ok {
 		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
@@ -141,7 +148,7 @@ func deletePostHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 4. 调用 service 层删除商品
-	err = service.DeletePost(postID, userID)
+	err = service.DeletePost(postID, actor)
 	if err != nil {
 		// 判断错误类型
 		if err.Error() == "record not found" {
@@ -152,6 +159,10 @@ func deletePostHandler(w http.ResponseWriter, r *http.Request) {
 			utils.SendErrorResponse(w, http.StatusForbidden, "You can only delete your own posts")
 			return
 		}
+		if err.Error() == "unauthorized: post is hidden by moderators" {
+			utils.SendErrorResponse(w, http.StatusForbidden, "This post has been hidden by moderators")
+			return
+		}
 		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to delete post: "+err.Error())
 		return
 	}
@@ -163,8 +174,8 @@ func deletePostHandler(w http.ResponseWriter, r *http.Request) {
 // editPostHandler 编辑商品信息
 // PUT /edit/{id}
 func editPostHandler(w http.ResponseWriter, r *http.Request) {
-	// 1. 从 Context 中获取用户ID
-	userID, ok := r.Context().Value("userID").(int)
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
 	if !ok {
 		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
@@ -184,6 +195,7 @@ func editPostHandler(w http.ResponseWriter, r *http.Request) {
 		Title       string  `json:"title"`
 		Description string  `json:"description"`
 		Price       float64 `json:"price"`
+		Category    string  `json:"category"` // 可选，不传时不修改
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -196,14 +208,19 @@ func editPostHandler(w http.ResponseWriter, r *http.Request) {
 		utils.SendErrorResponse(w, http.StatusBadRequest, "Title and valid price are required")
 		return
 	}
+	if req.Category != "" && !service.IsValidPostCategory(req.Category) {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid category")
+		return
+	}
 
 	// 5. 调用 service 层更新商品
 	post, err := service.UpdatePost(service.UpdatePostRequest{
 		PostID:      postID,
-		UserID:      userID,
+		Actor:       actor,
 		Title:       req.Title,
 		Description: req.Description,
 		Price:       req.Price,
+		Category:    req.Category,
 	})
 	if err != nil {
 		// 判断错误类型
@@ -215,6 +232,10 @@ func editPostHandler(w http.ResponseWriter, r *http.Request) {
 			utils.SendErrorResponse(w, http.StatusForbidden, "You can only edit your own posts")
 			return
 		}
+		if err.Error() == "unauthorized: post is hidden by moderators" {
+			utils.SendErrorResponse(w, http.StatusForbidden, "This post has been hidden by moderators")
+			return
+		}
 		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to update post: "+err.Error())
 		return
 	}
@@ -224,10 +245,11 @@ func editPostHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // updatePostStatusHandler 更新商品状态（例如标记为已售出）
-// PUT /items/{id}?status=sold
+// PUT /item/{id}/status?status=sold&buyer=<username>
+// 标记为已售出时可通过 buyer 指定买家，生成交易记录后双方可以互相评价
 func updatePostStatusHandler(w http.ResponseWriter, r *http.Request) {
-	// 1. 从 Context 中获取用户ID
-	userID, ok := r.Context().Value("userID").(int)
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
 	if !ok {
 		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
@@ -251,9 +273,10 @@ func updatePostStatusHandler(w http.ResponseWriter, r *http.Request) {
 
 	// 4. 调用 service 层更新商品状态
 	post, err := service.UpdatePostStatus(service.UpdatePostStatusRequest{
-		PostID: postID,
-		UserID: userID,
-		Status: status,
+		PostID:        postID,
+		Actor:         actor,
+		Status:        status,
+		BuyerUsername: r.URL.Query().Get("buyer"),
 	})
 	if err != nil {
 		// 判断错误类型
@@ -265,10 +288,30 @@ func updatePostStatusHandler(w http.ResponseWriter, r *http.Request) {
 			utils.SendErrorResponse(w, http.StatusForbidden, "You can only update your own posts")
 			return
 		}
+		if err.Error() == "unauthorized: post is hidden by moderators" {
+			utils.SendErrorResponse(w, http.StatusForbidden, "This post has been hidden by moderators")
+			return
+		}
 		if err.Error() == "invalid status: must be one of active, sold, deleted" {
 			utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		if err.Error() == "buyer not found" || err.Error() == "buyer cannot be the seller" {
+			utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err.Error() == "buyer has not contacted the seller" {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "The buyer must have viewed this post's contact info before a sale can be recorded")
+			return
+		}
+		if err.Error() == "buyer is blocked" {
+			utils.SendErrorResponse(w, http.StatusForbidden, "You cannot record a sale with this user")
+			return
+		}
+		if err.Error() == "sale already recorded for this post" {
+			utils.SendErrorResponse(w, http.StatusConflict, err.Error())
+			return
+		}
 		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to update post status: "+err.Error())
 		return
 	}
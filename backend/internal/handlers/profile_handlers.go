@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/internal/service"
+	"backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// usernameRegex 用户名只能包含字母、数字、下划线、点和连字符
+var usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// getMeHandler 获取我的账号信息
+// GET /me
+func getMeHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 调用 service 层查询
+	profile, err := service.GetProfile(userID)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get profile: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	utils.SendSuccessResponse(w, profile)
+}
+
+// updateMeHandler 更新个人资料（只更新传入的字段）
+// PATCH /me
+// 修改邮箱需要 current_password（第三方登录用户刚登录时可以不提供），新邮箱验证后才生效；用户名每 30 天只能修改一次
+func updateMeHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析请求体
+	var req struct {
+		DisplayName      *string `json:"display_name"`
+		Bio              *string `json:"bio"`
+		PreferredContact *string `json:"preferred_contact"`
+		Username         *string `json:"username"`
+		Email            *string `json:"email"`
+		CurrentPassword  string  `json:"current_password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// 3. 验证输入
+	if req.DisplayName != nil {
+		*req.DisplayName = strings.TrimSpace(*req.DisplayName)
+		if utf8.RuneCountInString(*req.DisplayName) > constants.MaxDisplayNameLength {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Display name is too long")
+			return
+		}
+	}
+	if req.Bio != nil && utf8.RuneCountInString(*req.Bio) > constants.MaxBioLength {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Bio is too long")
+		return
+	}
+	if req.PreferredContact != nil && !service.IsValidContactMethod(*req.PreferredContact) {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Preferred contact must be one of email, phone, text")
+		return
+	}
+	if req.Username != nil {
+		*req.Username = strings.TrimSpace(*req.Username)
+		if len(*req.Username) < constants.MinUsernameLength || len(*req.Username) > constants.MaxUsernameLength ||
+			!usernameRegex.MatchString(*req.Username) {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Username must be 3-50 characters of letters, numbers, '_', '.' or '-'")
+			return
+		}
+	}
+	if req.Email != nil {
+		*req.Email = strings.TrimSpace(*req.Email)
+		if !emailRegex.MatchString(*req.Email) {
+			utils.SendErrorResponse(w, http.StatusBadRequest, constants.ErrInvalidEmail)
+			return
+		}
+	}
+
+	// 4. 调用 service 层更新
+	sessionID, _ := r.Context().Value("sessionID").(string)
+	profile, err := service.UpdateProfile(service.UpdateProfileRequest{
+		UserID:           userID,
+		DisplayName:      req.DisplayName,
+		Bio:              req.Bio,
+		PreferredContact: req.PreferredContact,
+		Username:         req.Username,
+		Email:            req.Email,
+		CurrentPassword:  req.CurrentPassword,
+		SessionID:        sessionID,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "username was changed recently":
+			utils.SendErrorResponse(w, http.StatusTooManyRequests, "Username can only be changed once every 30 days")
+		case "username already taken":
+			utils.SendErrorResponse(w, http.StatusConflict, "Username is already taken")
+		case "email already in use":
+			utils.SendErrorResponse(w, http.StatusConflict, "Email is already in use")
+		case "current password is incorrect":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Current password is required to change email")
+		case "recent login required":
+			utils.SendErrorResponse(w, http.StatusForbidden, "Please log in again to change your email")
+		case "please wait before requesting another email":
+			utils.SendErrorResponse(w, http.StatusTooManyRequests, "Please wait before requesting another email")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to update profile: "+err.Error())
+		}
+		return
+	}
+
+	// 5. 返回成功响应（申请修改邮箱时记录审计日志）
+	if profile.PendingEmail != "" && req.Email != nil && strings.EqualFold(profile.PendingEmail, *req.Email) {
+		recordAuthEvent(r, userID, constants.AuditActionEmailChangeRequest, models.AuditState{"new_email_hash": service.AuditEmailHash(*req.Email)})
+		utils.SendSuccessWithMessage(w, "Profile updated, please check your new email address to confirm the change", profile)
+		return
+	}
+	utils.SendSuccessWithMessage(w, "Profile updated successfully", profile)
+}
+
+// uploadAvatarHandler 上传头像
+// POST /me/avatar
+func uploadAvatarHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析 multipart form（限制 10MB）
+	r.Body = http.MaxBytesReader(w, r.Body, constants.MaxFileSize+1<<20)
+	if err := r.ParseMultipartForm(constants.MaxFileSize); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Failed to parse form: "+err.Error())
+		return
+	}
+
+	// 3. 获取上传的图片
+	file, fileHeader, err := r.FormFile("avatar")
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Avatar image is required")
+		return
+	}
+	defer file.Close()
+
+	if !strings.HasPrefix(fileHeader.Header.Get("Content-Type"), "image/") {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Only image files are allowed")
+		return
+	}
+	if fileHeader.Size > constants.MaxFileSize {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Avatar image is too large")
+		return
+	}
+
+	// 4. 上传到 GCS
+	url, err := database.UploadFile(file, fileHeader)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to upload avatar: "+err.Error())
+		return
+	}
+
+	// 5. 更新头像地址
+	profile, err := service.UpdateAvatar(userID, url)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to update avatar: "+err.Error())
+		return
+	}
+
+	// 6. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Avatar updated successfully", profile)
+}
+
+// confirmEmailChangeHandler 使用邮件中的令牌确认修改邮箱
+// POST /confirm-email-change
+func confirmEmailChangeHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 解析请求体
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Token is required")
+		return
+	}
+
+	// 2. 调用 service 层确认
+	user, err := service.ConfirmEmailChange(req.Token)
+	if err != nil {
+		switch err.Error() {
+		case "invalid or expired token":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid or expired link")
+		case "email already in use":
+			utils.SendErrorResponse(w, http.StatusConflict, "Email is already in use")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to change email: "+err.Error())
+		}
+		return
+	}
+
+	// 3. 记录审计日志并返回成功响应（所有会话已注销，需要用新邮箱重新登录）
+	recordAuthEvent(r, user.ID, constants.AuditActionEmailChange, models.AuditState{"new_email_hash": service.AuditEmailHash(user.Email)})
+	utils.SendSuccessWithMessage(w, "Email changed successfully, please log in again", user)
+}
+
+// getSellerProfileHandler 获取卖家公开主页（不需要登录）
+// GET /users/{username}?page=1&page_size=8
+func getSellerProfileHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 获取分页参数
+	page, pageSize := parsePagination(r, 8)
+
+	// 2. 调用 service 层获取数据
+	resp, err := service.GetSellerProfile(service.GetSellerProfileRequest{
+		Username: mux.Vars(r)["username"],
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		if err.Error() == "record not found" {
+			utils.SendErrorResponse(w, http.StatusNotFound, constants.ErrUserNotFound)
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get user profile: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	utils.SendSuccessResponse(w, resp)
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	"backend/internal/constants"
+	"backend/internal/service"
+	"backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// reportPostHandler 举报商品
+// POST /item/{id}/report
+func reportPostHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取商品ID
+	postID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid post ID")
+		return
+	}
+
+	// 3. 解析请求体
+	var req struct {
+		Reason  string `json:"reason"`
+		Details string `json:"details"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if utf8.RuneCountInString(req.Details) > constants.MaxReportDetailsLength {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Details are too long")
+		return
+	}
+
+	// 4. 调用 service 层举报商品
+	report, err := service.ReportPost(service.ReportPostRequest{
+		PostID:     postID,
+		ReporterID: userID,
+		Reason:     req.Reason,
+		Details:    req.Details,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "record not found":
+			utils.SendErrorResponse(w, http.StatusNotFound, "Post not found")
+		case "invalid reason":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Reason must be one of scam, prohibited, counterfeit, spam, offensive, other")
+		case "you cannot report your own post":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "You cannot report your own post")
+		case "post already reported":
+			utils.SendErrorResponse(w, http.StatusConflict, "You have already reported this post")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to report post: "+err.Error())
+		}
+		return
+	}
+
+	// 5. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Report submitted, thank you", report)
+}
+
+// getModerationQueueHandler 获取审核队列（管理员）
+// GET /admin/reports?status=pending&page=1&page_size=20
+func getModerationQueueHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 获取分页参数
+	page, pageSize := parsePagination(r, 20)
+
+	// 3. 调用 service 层获取数据
+	resp, err := service.GetModerationQueue(service.GetModerationQueueRequest{
+		Actor:    actor,
+		Status:   r.URL.Query().Get("status"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		if err.Error() == "unauthorized: you cannot moderate reports" {
+			utils.SendErrorResponse(w, http.StatusForbidden, "You do not have permission to moderate reports")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get reports: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessResponse(w, resp)
+}
+
+// getReportedPostHandler 查看被举报商品的举报和历史审核决定（管理员）
+// GET /admin/reports/posts/{id}
+func getReportedPostHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取商品ID
+	postID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid post ID")
+		return
+	}
+
+	// 3. 调用 service 层获取数据
+	detail, err := service.GetReportedPost(actor, postID)
+	if err != nil {
+		switch err.Error() {
+		case "record not found":
+			utils.SendErrorResponse(w, http.StatusNotFound, "Post not found")
+		case "unauthorized: you cannot moderate reports":
+			utils.SendErrorResponse(w, http.StatusForbidden, "You do not have permission to moderate reports")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get reports: "+err.Error())
+		}
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessResponse(w, detail)
+}
+
+// decideReportsHandler 处理商品的待处理举报：驳回、隐藏商品或封禁卖家（管理员）
+// POST /admin/reports/posts/{id}/decision
+func decideReportsHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取商品ID
+	postID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid post ID")
+		return
+	}
+
+	// 3. 解析请求体
+	var req struct {
+		Action string `json:"action"`
+		Note   string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// 4. 调用 service 层处理举报
+	decision, err := service.DecideReports(service.DecideReportsRequest{
+		Actor:  actor,
+		PostID: postID,
+		Action: req.Action,
+		Note:   req.Note,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "record not found":
+			utils.SendErrorResponse(w, http.StatusNotFound, "Post not found")
+		case "invalid action":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Action must be one of dismiss, hide_post, ban_user")
+		case "unauthorized: you cannot moderate reports":
+			utils.SendErrorResponse(w, http.StatusForbidden, "You do not have permission to moderate reports")
+		case "cannot suspend an admin":
+			utils.SendErrorResponse(w, http.StatusConflict, "Cannot ban an admin")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to record decision: "+err.Error())
+		}
+		return
+	}
+
+	// 5. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Decision recorded", decision)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/internal/constants"
+	"backend/internal/service"
+	"backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// myTransactionsHandler 获取我参与的交易（作为买家或卖家）
+// GET /transactions?page=1&page_size=10
+func myTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 获取分页参数
+	page, pageSize := parsePagination(r, 10)
+
+	// 3. 调用 service 层获取数据
+	resp, err := service.GetMyTransactions(service.GetMyTransactionsRequest{
+		UserID:   userID,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get transactions: "+err.Error())
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessResponse(w, resp)
+}
+
+// submitReviewHandler 对交易提交评价
+// POST /transactions/{id}/review
+func submitReviewHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取交易ID
+	transactionID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid transaction ID")
+		return
+	}
+
+	// 3. 解析请求体
+	var req struct {
+		Rating  int    `json:"rating"`
+		Comment string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// 4. 验证评价内容长度
+	if len(req.Comment) > constants.MaxReviewCommentLength {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Comment is too long")
+		return
+	}
+
+	// 5. 调用 service 层提交评价
+	review, err := service.SubmitReview(service.SubmitReviewRequest{
+		TransactionID: transactionID,
+		ReviewerID:    userID,
+		Rating:        req.Rating,
+		Comment:       req.Comment,
+	})
+	if err != nil {
+		// 判断错误类型
+		if err.Error() == "record not found" {
+			utils.SendErrorResponse(w, http.StatusNotFound, "Transaction not found")
+			return
+		}
+		if err.Error() == "unauthorized: you are not part of this transaction" {
+			utils.SendErrorResponse(w, http.StatusForbidden, "You can only review your own transactions")
+			return
+		}
+		if err.Error() == "review already submitted" {
+			utils.SendErrorResponse(w, http.StatusConflict, "You have already reviewed this transaction")
+			return
+		}
+		if err.Error() == "review window has closed" || strings.HasPrefix(err.Error(), "invalid rating") {
+			utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to submit review: "+err.Error())
+		return
+	}
+
+	// 6. 返回成功响应
+	utils.SendSuccessWithMessage(w, "Review submitted successfully", review)
+}
+
+// getUserReviewsHandler 获取用户收到的已公开评价
+// GET /users/{id}/reviews?as=seller&page=1&page_size=10
+func getUserReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从路径参数中获取用户ID
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 2. 确认用户存在
+	if _, err := service.GetUserByID(userID); err != nil {
+		utils.SendErrorResponse(w, http.StatusNotFound, constants.ErrUserNotFound)
+		return
+	}
+
+	// 3. 获取分页参数
+	page, pageSize := parsePagination(r, 10)
+
+	// 4. 调用 service 层获取数据
+	resp, err := service.GetUserReviews(service.GetUserReviewsRequest{
+		UserID:   userID,
+		As:       r.URL.Query().Get("as"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid role") {
+			utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get reviews: "+err.Error())
+		return
+	}
+
+	// 5. 返回成功响应
+	utils.SendSuccessResponse(w, resp)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"backend/internal/constants"
 	"backend/internal/middleware"
 
 	"github.com/gorilla/mux"
@@ -14,32 +15,135 @@ func InitRouter() *mux.Router {
 
 	// 应用 CORS 中间件到所有路由（必须放在最前面）
 	router.Use(middleware.CORSMiddleware)
+	router.Use(middleware.RequestIDMiddleware) // 为每个请求分配请求ID（审计日志使用）
 
 	// ========================================
 	// 公开路由（不需要登录）
 	// ========================================
 	router.HandleFunc("/register", registerHandler).Methods("POST", "OPTIONS")
 	router.HandleFunc("/login", loginHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/login/2fa", loginTwoFactorHandler).Methods("POST", "OPTIONS") // 登录第二步：提交两步验证码
+	router.HandleFunc("/refresh", refreshTokenHandler).Methods("POST", "OPTIONS")     // 刷新访问令牌
+	router.HandleFunc("/verify-email", verifyEmailHandler).Methods("POST", "OPTIONS") // 验证邮箱
+	router.HandleFunc("/forgot-password", forgotPasswordHandler).Methods("POST", "OPTIONS") // 发送密码重置邮件
+	router.HandleFunc("/reset-password", resetPasswordHandler).Methods("POST", "OPTIONS")   // 重置密码
+	router.HandleFunc("/confirm-email-change", confirmEmailChangeHandler).Methods("POST", "OPTIONS") // 确认修改邮箱
+	router.HandleFunc("/auth/oidc/login", oidcLoginHandler).Methods("GET", "OPTIONS")        // 发起第三方登录
+	router.HandleFunc("/auth/oidc/callback", oidcCallbackHandler).Methods("POST", "OPTIONS") // 第三方登录回调
+	router.HandleFunc("/.well-known/jwks.json", jwksHandler).Methods("GET", "OPTIONS")       // JWT 签名公钥
+	router.HandleFunc("/users/{username}", getSellerProfileHandler).Methods("GET", "OPTIONS") // 卖家公开主页
 
 	// ========================================
 	// 受保护的路由（需要登录）
 	// ========================================
 	// 创建受保护的子路由器
+	// 用 middleware.Scope 声明的路由也可以使用个人访问令牌访问（需要对应的权限范围）
 	protected := router.PathPrefix("/").Subrouter()
 	
 	// 应用认证中间件到所有受保护的路由
 	protected.Use(middleware.AuthMiddleware)
 
+	// 会话相关路由（需要认证）
+	protected.HandleFunc("/logout", logoutHandler).Methods("POST", "OPTIONS")         // 退出当前会话
+	protected.HandleFunc("/logout-all", logoutAllHandler).Methods("POST", "OPTIONS") // 退出所有会话
+	protected.HandleFunc("/sessions", getSessionsHandler).Methods("GET", "OPTIONS")          // 我登录的会话（设备）
+	protected.HandleFunc("/sessions/{id}", revokeSessionHandler).Methods("DELETE", "OPTIONS") // 注销指定会话
+	protected.HandleFunc("/verify-email/resend", resendVerificationHandler).Methods("POST", "OPTIONS") // 重新发送验证邮件
+	protected.HandleFunc("/change-password", changePasswordHandler).Methods("POST", "OPTIONS")         // 修改密码
+
+	// 个人资料相关路由（需要认证）
+	protected.HandleFunc("/me", getMeHandler).Methods("GET", "OPTIONS")                  // 我的账号信息
+	protected.HandleFunc("/me", updateMeHandler).Methods("PATCH", "OPTIONS")             // 更新个人资料
+	protected.HandleFunc("/me/avatar", uploadAvatarHandler).Methods("POST", "OPTIONS")   // 上传头像
+	protected.HandleFunc("/me/export", exportAccountHandler).Methods("GET", "OPTIONS")   // 下载我的个人数据（ZIP）
+	protected.HandleFunc("/me", deleteAccountHandler).Methods("DELETE", "OPTIONS")       // 申请删除账号
+	protected.HandleFunc("/me/deletion", cancelAccountDeletionHandler).Methods("DELETE", "OPTIONS") // 取消删除账号
+
+	// 个人访问令牌管理（只能使用登录令牌访问）
+	protected.HandleFunc("/tokens", getPersonalAccessTokensHandler).Methods("GET", "OPTIONS")            // 我的个人访问令牌
+	protected.HandleFunc("/tokens", createPersonalAccessTokenHandler).Methods("POST", "OPTIONS")         // 创建个人访问令牌
+	protected.HandleFunc("/tokens/{id}", deletePersonalAccessTokenHandler).Methods("DELETE", "OPTIONS") // 删除个人访问令牌
+
+	// 两步验证相关路由（需要认证）
+	protected.HandleFunc("/2fa/enroll", enrollTwoFactorHandler).Methods("POST", "OPTIONS")             // 生成密钥
+	protected.HandleFunc("/2fa/confirm", confirmTwoFactorHandler).Methods("POST", "OPTIONS")           // 确认开启，返回备用码
+	protected.HandleFunc("/2fa/disable", disableTwoFactorHandler).Methods("POST", "OPTIONS")           // 关闭两步验证
+	protected.HandleFunc("/2fa/backup-codes", regenerateBackupCodesHandler).Methods("POST", "OPTIONS") // 重新生成备用码
+
 	// 商品相关路由（需要认证）
-	protected.HandleFunc("/items", getPostsHandler).Methods("GET", "OPTIONS")                // 浏览所有商品（需要登录）
-	protected.HandleFunc("/item/{id}", getPostByIDHandler).Methods("GET", "OPTIONS")         // 获取商品详情
-	protected.HandleFunc("/item/{id}/status", updatePostStatusHandler).Methods("PUT", "OPTIONS") // 更新商品状态（标记为已售出等）
-	protected.HandleFunc("/item/{id}", editPostHandler).Methods("PUT", "OPTIONS")            // 更新商品
-	protected.HandleFunc("/item/{id}", deletePostHandler).Methods("DELETE", "OPTIONS")       // 删除商品（软删除）
-	protected.HandleFunc("/mylistings", myListingsHandler).Methods("GET", "OPTIONS")         // 我的商品列表
+	middleware.Scope(protected.HandleFunc("/items", getPostsHandler).Methods("GET", "OPTIONS"), constants.ScopePostsRead) // 浏览所有商品（需要登录）
+	middleware.Scope(protected.HandleFunc("/item/{id}", getPostByIDHandler).Methods("GET", "OPTIONS"), constants.ScopePostsRead) // 获取商品详情
+	middleware.Scope(protected.HandleFunc("/item/{id}/status", updatePostStatusHandler).Methods("PUT", "OPTIONS"), constants.ScopePostsWrite) // 更新商品状态（标记为已售出等）
+	middleware.Scope(protected.HandleFunc("/item/{id}", editPostHandler).Methods("PUT", "OPTIONS"), constants.ScopePostsWrite) // 更新商品
+	middleware.Scope(protected.HandleFunc("/item/{id}", deletePostHandler).Methods("DELETE", "OPTIONS"), constants.ScopePostsWrite) // 删除商品（软删除）
+	middleware.Scope(protected.HandleFunc("/mylistings", myListingsHandler).Methods("GET", "OPTIONS"), constants.ScopePostsRead) // 我的商品列表
+	protected.HandleFunc("/item/{id}/contact-reveals", getContactRevealsHandler).Methods("GET", "OPTIONS") // 谁查看过商品的联系方式（卖家）
+	protected.HandleFunc("/item/{id}/report", reportPostHandler).Methods("POST", "OPTIONS")               // 举报商品
+
+	// 收藏相关路由（需要认证）
+	middleware.Scope(protected.HandleFunc("/item/{id}/favorite", addFavoriteHandler).Methods("POST", "OPTIONS"), constants.ScopeFavoritesWrite) // 收藏商品
+	middleware.Scope(protected.HandleFunc("/item/{id}/favorite", removeFavoriteHandler).Methods("DELETE", "OPTIONS"), constants.ScopeFavoritesWrite) // 取消收藏
+	middleware.Scope(protected.HandleFunc("/favorites", getFavoritesHandler).Methods("GET", "OPTIONS"), constants.ScopeFavoritesRead) // 我的收藏列表
+
+	// 交易与评价相关路由（需要认证）
+	protected.HandleFunc("/transactions", myTransactionsHandler).Methods("GET", "OPTIONS")                // 我参与的交易
+	protected.HandleFunc("/transactions/{id}/review", submitReviewHandler).Methods("POST", "OPTIONS")     // 对交易提交评价
+	protected.HandleFunc("/users/{id}/reviews", getUserReviewsHandler).Methods("GET", "OPTIONS")          // 用户收到的评价
+
+	// 屏蔽相关路由（需要认证）
+	protected.HandleFunc("/blocks", getBlockedUsersHandler).Methods("GET", "OPTIONS")       // 我屏蔽的用户
+	protected.HandleFunc("/blocks", blockUserHandler).Methods("POST", "OPTIONS")            // 屏蔽用户
+	protected.HandleFunc("/blocks/{id}", unblockUserHandler).Methods("DELETE", "OPTIONS")   // 取消屏蔽
+
+	// 关注相关路由（需要认证）
+	protected.HandleFunc("/users/{id}/follow", followSellerHandler).Methods("POST", "OPTIONS")     // 关注卖家
+	protected.HandleFunc("/users/{id}/follow", unfollowSellerHandler).Methods("DELETE", "OPTIONS") // 取消关注
+	protected.HandleFunc("/following", getFollowingHandler).Methods("GET", "OPTIONS")              // 我关注的卖家
+	middleware.Scope(protected.HandleFunc("/feed", getFeedHandler).Methods("GET", "OPTIONS"), constants.ScopePostsRead) // 关注的卖家发布的商品
+	protected.HandleFunc("/feed/read-all", markFeedReadHandler).Methods("PUT", "OPTIONS")          // 动态全部标记为已读
+
+	// 通知相关路由（需要认证）
+	middleware.Scope(protected.HandleFunc("/notifications", getNotificationsHandler).Methods("GET", "OPTIONS"), constants.ScopeNotificationsRead) // 通知列表
+	middleware.Scope(protected.HandleFunc("/notifications/unread-count", getUnreadNotificationCountHandler).Methods("GET", "OPTIONS"), constants.ScopeNotificationsRead) // 未读通知数量
+	protected.HandleFunc("/notifications/read-all", markAllNotificationsReadHandler).Methods("PUT", "OPTIONS")           // 全部标记为已读
+	protected.HandleFunc("/notifications/preferences", getNotificationPreferencesHandler).Methods("GET", "OPTIONS")      // 获取通知偏好
+	protected.HandleFunc("/notifications/preferences", updateNotificationPreferencesHandler).Methods("PUT", "OPTIONS")   // 更新通知偏好
+	protected.HandleFunc("/notifications/{id}/read", markNotificationReadHandler).Methods("PUT", "OPTIONS")              // 标记为已读
+
+	// ========================================
+	// 需要邮箱已验证的路由（发布商品、发送消息等）
+	// ========================================
+	verified := protected.NewRoute().Subrouter()
+	verified.Use(middleware.RequireVerifiedEmail)
 
 	// 上传相关路由（需要认证）
-	protected.HandleFunc("/upload", uploadNewPostHandler).Methods("POST", "OPTIONS")      // 上传新商品（含图片）
+	middleware.Scope(verified.HandleFunc("/upload", uploadNewPostHandler).Methods("POST", "OPTIONS"), constants.ScopePostsWrite) // 上传新商品（含图片）
+
+	// 联系方式相关路由（列表和详情中的联系方式已隐藏，不支持个人访问令牌）
+	verified.HandleFunc("/item/{id}/contact", revealContactHandler).Methods("POST", "OPTIONS") // 查看完整联系方式
+
+	// ========================================
+	// 管理员路由
+	// ========================================
+	admin := protected.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.RequireRole(constants.RoleAdmin))
+
+	admin.HandleFunc("/users/{id}/role", updateUserRoleHandler).Methods("PUT", "OPTIONS") // 修改用户角色
+	admin.HandleFunc("/users/{id}/suspend", suspendUserHandler).Methods("POST", "OPTIONS")      // 封禁用户
+	admin.HandleFunc("/users/{id}/suspend", liftSuspensionHandler).Methods("DELETE", "OPTIONS")  // 解除封禁
+
+	// 举报审核
+	admin.HandleFunc("/reports", getModerationQueueHandler).Methods("GET", "OPTIONS")                       // 审核队列
+	admin.HandleFunc("/reports/posts/{id}", getReportedPostHandler).Methods("GET", "OPTIONS")               // 被举报商品详情
+	admin.HandleFunc("/reports/posts/{id}/decision", decideReportsHandler).Methods("POST", "OPTIONS")       // 处理举报
+
+	// 审计日志
+	admin.HandleFunc("/audit-logs", getAuditLogsHandler).Methods("GET", "OPTIONS") // 查询审计日志
+
+	// 运营统计（结果缓存几分钟）
+	admin.HandleFunc("/stats/users", getUserStatsHandler).Methods("GET", "OPTIONS")            // 每天新注册用户数
+	admin.HandleFunc("/stats/posts", getPostStatsHandler).Methods("GET", "OPTIONS")            // 每天发布、售出、删除的商品数
+	admin.HandleFunc("/stats/summary", getMarketplaceSummaryHandler).Methods("GET", "OPTIONS") // 在售商品数、售出用时、价格中位数
 
 	return router
 }
\ No newline at end of file
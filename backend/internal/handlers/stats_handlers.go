@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/service"
+	"backend/pkg/utils"
+)
+
+// statsRangeFromRequest 从查询参数 from、to（YYYY-MM-DD 或 RFC3339）中解析统计日期范围
+// 解析失败时已写入错误响应，返回 false
+func statsRangeFromRequest(w http.ResponseWriter, r *http.Request) (service.StatsRange, bool) {
+	from, err := parseTimeParam(r, "from")
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid from date, expected YYYY-MM-DD")
+		return service.StatsRange{}, false
+	}
+	to, err := parseTimeParam(r, "to")
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid to date, expected YYYY-MM-DD")
+		return service.StatsRange{}, false
+	}
+
+	statsRange, err := service.NewStatsRange(from, to)
+	if err != nil {
+		switch err.Error() {
+		case "invalid date range":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "From date must not be after to date")
+		case "date range too long":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Date range is too long")
+		default:
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid date range")
+		}
+		return service.StatsRange{}, false
+	}
+	return statsRange, true
+}
+
+// sendStatsError 返回统计接口的错误响应
+func sendStatsError(w http.ResponseWriter, err error) {
+	if err.Error() == "unauthorized: you cannot view stats" {
+		utils.SendErrorResponse(w, http.StatusForbidden, "You do not have permission to view stats")
+		return
+	}
+	utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get stats: "+err.Error())
+}
+
+// getUserStatsHandler 每天的新注册用户数（管理员）
+// GET /admin/stats/users?from=2024-01-01&to=2024-01-31
+func getUserStatsHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析日期范围
+	statsRange, ok := statsRangeFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// 3. 调用 service 层统计
+	stats, err := service.GetUserStats(actor, statsRange)
+	if err != nil {
+		sendStatsError(w, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessResponse(w, stats)
+}
+
+// getPostStatsHandler 每天发布、售出、删除的商品数（管理员）
+// GET /admin/stats/posts?from=2024-01-01&to=2024-01-31
+func getPostStatsHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析日期范围
+	statsRange, ok := statsRangeFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// 3. 调用 service 层统计
+	stats, err := service.GetPostStats(actor, statsRange)
+	if err != nil {
+		sendStatsError(w, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessResponse(w, stats)
+}
+
+// getMarketplaceSummaryHandler 在售商品数、售出用时和价格中位数（管理员）
+// GET /admin/stats/summary?from=2024-01-01&to=2024-01-31
+func getMarketplaceSummaryHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取操作者
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析日期范围
+	statsRange, ok := statsRangeFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// 3. 调用 service 层统计
+	summary, err := service.GetMarketplaceSummary(actor, statsRange)
+	if err != nil {
+		sendStatsError(w, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	utils.SendSuccessResponse(w, summary)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/internal/constants"
+	"backend/internal/models"
+	"backend/internal/service"
+	"backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// getPersonalAccessTokensHandler 获取我的个人访问令牌列表（不包含令牌明文）
+// GET /tokens
+func getPersonalAccessTokensHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 调用 service 层查询
+	tokens, err := service.GetPersonalAccessTokens(userID)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to get tokens: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	utils.SendSuccessResponse(w, tokens)
+}
+
+// createPersonalAccessTokenHandler 创建个人访问令牌（令牌明文只在创建时返回一次）
+// POST /tokens
+func createPersonalAccessTokenHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析请求体
+	var req struct {
+		Name          string   `json:"name"`
+		Scopes        []string `json:"scopes"`
+		ExpiresInDays int      `json:"expires_in_days"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// 3. 调用 service 层创建令牌
+	token, err := service.CreatePersonalAccessToken(service.CreatePersonalAccessTokenRequest{
+		UserID:        userID,
+		Name:          req.Name,
+		Scopes:        req.Scopes,
+		ExpiresInDays: req.ExpiresInDays,
+	})
+	if err != nil {
+		switch {
+		case err.Error() == "too many personal access tokens":
+			utils.SendErrorResponse(w, http.StatusConflict, "Too many personal access tokens, please delete unused ones")
+		case strings.HasPrefix(err.Error(), "name must"),
+			strings.HasPrefix(err.Error(), "invalid scope"),
+			strings.HasPrefix(err.Error(), "expires_in_days must"),
+			err.Error() == "at least one scope is required":
+			utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to create token: "+err.Error())
+		}
+		return
+	}
+
+	// 4. 记录审计日志并返回成功响应
+	recordAuthEvent(r, userID, constants.AuditActionTokenCreate, models.AuditState{
+		"token_id": token.ID,
+		"name":     token.Name,
+		"scopes":   token.Scopes,
+	})
+	utils.SendSuccessWithMessage(w, "Token created, copy it now as it will not be shown again", token)
+}
+
+// deletePersonalAccessTokenHandler 删除（撤销）个人访问令牌
+// DELETE /tokens/{id}
+func deletePersonalAccessTokenHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 从路径参数中获取令牌ID
+	tokenID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid token ID")
+		return
+	}
+
+	// 3. 调用 service 层删除
+	if err := service.DeletePersonalAccessToken(userID, tokenID); err != nil {
+		if err.Error() == "record not found" {
+			utils.SendErrorResponse(w, http.StatusNotFound, "Token not found")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to delete token: "+err.Error())
+		return
+	}
+
+	// 4. 记录审计日志并返回成功响应
+	recordAuthEvent(r, userID, constants.AuditActionTokenRevoke, models.AuditState{"token_id": tokenID})
+	utils.SendSuccessWithMessage(w, "Token deleted successfully", nil)
+}
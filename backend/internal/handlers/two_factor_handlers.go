@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+
+	"backend/internal/constants"
+	"backend/internal/models"
+	"backend/internal/service"
+	"backend/pkg/utils"
+)
+
+// TwoFactorChallengeResponse 开启两步验证的用户密码验证通过后的响应
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"` // 提交验证码时带上，短期有效
+}
+
+// BackupCodesResponse 备用码响应（明文只返回这一次）
+type BackupCodesResponse struct {
+	BackupCodes []string `json:"backup_codes"`
+}
+
+// loginTwoFactorHandler 登录第二步：提交验证码或备用码
+// POST /login/2fa
+func loginTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 解析请求体
+	var req struct {
+		ChallengeToken string `json:"challenge_token"`
+		Code           string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.ChallengeToken == "" || req.Code == "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Challenge token and code are required")
+		return
+	}
+
+	// 2. 验证挑战令牌
+	claims, err := utils.ValidateChallengeToken(req.ChallengeToken)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Invalid or expired challenge token")
+		return
+	}
+	user, err := service.GetUserByID(claims.UserID)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Invalid or expired challenge token")
+		return
+	}
+
+	// 3. 验证码的失败次数与密码共用登录限制
+	ip := utils.ClientIP(r)
+	email := user.Email
+	retryAfter, err := service.CheckLoginAllowed(email, ip)
+	if err != nil {
+		if err.Error() == "too many login attempts" {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			utils.SendErrorResponse(w, http.StatusTooManyRequests, "Too many login attempts, please try again later")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to log in")
+		return
+	}
+
+	// 4. 验证验证码或备用码
+	user, err = service.VerifyTwoFactorLogin(claims.UserID, req.Code)
+	if err != nil {
+		if err.Error() == "invalid two-factor code" || err.Error() == "two-factor authentication not enabled" {
+			recordLoginAttempt(r, email, false)
+			recordAuthEvent(r, claims.UserID, constants.AuditActionLoginFailed, models.AuditState{"method": "2fa"})
+			utils.SendErrorResponse(w, http.StatusUnauthorized, "Invalid two-factor code")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to log in")
+		return
+	}
+	recordLoginAttempt(r, email, true)
+	recordAuthEvent(r, user.ID, constants.AuditActionLogin, models.AuditState{"method": "2fa"})
+	if user.IsSuspended() {
+		utils.SendErrorResponse(w, http.StatusForbidden, service.SuspensionMessage(user))
+		return
+	}
+
+	// 5. 创建登录会话并签发令牌
+	tokens, err := service.IssueTokens(user.ID, sessionClientFromRequest(r))
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	// 6. 返回响应
+	utils.SendSuccessResponse(w, newAuthResponse(tokens, user))
+}
+
+// enrollTwoFactorHandler 开始开启两步验证，返回密钥和 otpauth 链接（用于生成二维码）
+// POST /2fa/enroll
+func enrollTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 调用 service 层生成密钥
+	enrollment, err := service.StartTwoFactorEnrollment(userID)
+	if err != nil {
+		if err.Error() == "two-factor authentication already enabled" {
+			utils.SendErrorResponse(w, http.StatusConflict, "Two-factor authentication is already enabled")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to start two-factor enrollment: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	utils.SendSuccessResponse(w, enrollment)
+}
+
+// confirmTwoFactorHandler 提交验证器生成的验证码，确认开启两步验证
+// POST /2fa/confirm
+func confirmTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析请求体
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Code is required")
+		return
+	}
+
+	// 3. 调用 service 层确认
+	backupCodes, err := service.ConfirmTwoFactor(userID, req.Code)
+	if err != nil {
+		switch err.Error() {
+		case "invalid two-factor code":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid two-factor code")
+		case "two-factor enrollment not started":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Two-factor enrollment has not been started")
+		case "two-factor authentication already enabled":
+			utils.SendErrorResponse(w, http.StatusConflict, "Two-factor authentication is already enabled")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to enable two-factor authentication: "+err.Error())
+		}
+		return
+	}
+
+	// 4. 记录审计日志并返回备用码
+	recordAuthEvent(r, userID, constants.AuditActionTwoFactorEnable, nil)
+	utils.SendSuccessWithMessage(w, "Two-factor authentication enabled", BackupCodesResponse{BackupCodes: backupCodes})
+}
+
+// disableTwoFactorHandler 关闭两步验证
+// POST /2fa/disable
+func disableTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析请求体
+	var req struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Password == "" || req.Code == "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Password and code are required")
+		return
+	}
+
+	// 3. 密码和验证码的失败次数与登录共用限制
+	email, ok := checkPasswordAttempt(w, r, userID)
+	if !ok {
+		return
+	}
+
+	// 4. 调用 service 层关闭
+	if err := service.DisableTwoFactor(userID, req.Password, req.Code); err != nil {
+		switch err.Error() {
+		case "current password is incorrect":
+			recordLoginAttempt(r, email, false)
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Password is incorrect")
+		case "invalid two-factor code":
+			recordLoginAttempt(r, email, false)
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid two-factor code")
+		case "two-factor authentication not enabled":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Two-factor authentication is not enabled")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to disable two-factor authentication: "+err.Error())
+		}
+		return
+	}
+
+	// 5. 记录审计日志并返回成功响应
+	recordLoginAttempt(r, email, true)
+	recordAuthEvent(r, userID, constants.AuditActionTwoFactorDisable, nil)
+	utils.SendSuccessWithMessage(w, "Two-factor authentication disabled", nil)
+}
+
+// regenerateBackupCodesHandler 重新生成备用码
+// POST /2fa/backup-codes
+func regenerateBackupCodesHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. 从 Context 中获取用户ID
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// 2. 解析请求体
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Code is required")
+		return
+	}
+
+	// 3. 调用 service 层重新生成
+	backupCodes, err := service.RegenerateBackupCodes(userID, req.Code)
+	if err != nil {
+		switch err.Error() {
+		case "invalid two-factor code":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid two-factor code")
+		case "two-factor authentication not enabled":
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Two-factor authentication is not enabled")
+		default:
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to regenerate backup codes: "+err.Error())
+		}
+		return
+	}
+
+	// 4. 返回备用码
+	utils.SendSuccessResponse(w, BackupCodesResponse{BackupCodes: backupCodes})
+}
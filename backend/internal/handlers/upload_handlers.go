@@ -33,6 +33,7 @@ func uploadNewPostHandler(w http.ResponseWriter, r *http.Request) {
 	contactInfo := r.FormValue("contact_info")
 	zipCode := r.FormValue("zip_code")
 	negotiableStr := r.FormValue("negotiable")
+	category := r.FormValue("category")
 
 	// 4. 验证必填字段
 	if title == "" || priceStr == "" || contactInfo == "" || zipCode == "" {
@@ -40,6 +41,11 @@ func uploadNewPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if category != "" && !service.IsValidPostCategory(category) {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "Invalid category")
+		return
+	}
+
 	// 5. 转换 price
 	price, err := strconv.ParseFloat(priceStr, 64)
 	if err != nil || price <= 0 {
@@ -101,6 +107,7 @@ func uploadNewPostHandler(w http.ResponseWriter, r *http.Request) {
 		Price:       price,
 		ContactInfo: contactInfo,
 		ZipCode:     zipCode,
+		Category:    category,
 		Negotiable:  negotiable,
 		ImageURLs:   imageURLs,
 	})
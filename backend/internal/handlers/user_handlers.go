@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"log"
+	"math"
 	"net/http"
 	"regexp"
+	"strconv"
 
 	"backend/internal/constants"
 	"backend/internal/models"
@@ -26,8 +29,34 @@ type LoginRequest struct {
 
 // AuthResponse 认证响应结构
 type AuthResponse struct {
-	Token string       `json:"token"`
-	User  models.User  `json:"user"`
+	Token        string      `json:"token"`         // 访问令牌
+	RefreshToken string      `json:"refresh_token"` // 刷新令牌
+	ExpiresIn    int         `json:"expires_in"`    // 访问令牌有效期（秒）
+	User         models.User `json:"user"`
+}
+
+// newAuthResponse 根据签发的令牌构建认证响应
+func newAuthResponse(tokens *service.TokenPair, user *models.User) AuthResponse {
+	return AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         *user,
+	}
+}
+
+// emailRegex 邮箱格式
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
+// validatePassword 验证密码长度，不合法时返回错误消息
+func validatePassword(password string) string {
+	if len(password) < constants.MinPasswordLength {
+		return constants.ErrPasswordTooShort
+	}
+	if len(password) > constants.MaxPasswordLength {
+		return constants.ErrPasswordTooLong
+	}
+	return ""
 }
 
 // registerHandler 用户注册
@@ -47,20 +76,14 @@ func registerHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 验证邮箱格式
-	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	if !emailRegex.MatchString(req.Email) {
 		utils.SendErrorResponse(w, http.StatusBadRequest, constants.ErrInvalidEmail)
 		return
 	}
 
 	// 验证密码长度
-	if len(req.Password) < constants.MinPasswordLength {
-		utils.SendErrorResponse(w, http.StatusBadRequest, constants.ErrPasswordTooShort)
-		return
-	}
-
-	if len(req.Password) > constants.MaxPasswordLength {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "Password is too long")
+	if msg := validatePassword(req.Password); msg != "" {
+		utils.SendErrorResponse(w, http.StatusBadRequest, msg)
 		return
 	}
 
@@ -84,19 +107,15 @@ func registerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 6. 生成JWT token
-	token, err := utils.GenerateToken(user.ID)
+	// 6. 创建登录会话并签发令牌
+	tokens, err := service.IssueTokens(user.ID, sessionClientFromRequest(r))
 	if err != nil {
 		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to generate token")
 		return
 	}
 
 	// 7. 返回响应
-	response := AuthResponse{
-		Token: token,
-		User:  *user,
-	}
-	utils.SendSuccessResponse(w, response)
+	utils.SendSuccessResponse(w, newAuthResponse(tokens, user))
 }
 
 // loginHandler 用户登录
@@ -114,31 +133,95 @@ func loginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 3. 查找用户
+	// 3. 检查是否因失败次数过多而被限制（按邮箱和IP）
+	ip := utils.ClientIP(r)
+	retryAfter, err := service.CheckLoginAllowed(req.Email, ip)
+	if err != nil {
+		if err.Error() == "too many login attempts" {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			utils.SendErrorResponse(w, http.StatusTooManyRequests, "Too many login attempts, please try again later")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to log in")
+		return
+	}
+
+	// 4. 查找用户并验证密码
+	// 用户不存在时同样执行一次密码比较，使响应时间一致，避免通过耗时判断邮箱是否已注册
 	user, err := service.GetUserByEmail(req.Email)
 	if err != nil {
+		utils.CheckDummyPassword(req.Password)
+		recordLoginAttempt(r, req.Email, false)
+		recordAuthEvent(r, 0, constants.AuditActionLoginFailed, models.AuditState{"email_hash": service.AuditEmailHash(req.Email), "method": "password"})
 		utils.SendErrorResponse(w, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
-
-	// 4. 验证密码
 	if err := utils.CheckPassword(user.PasswordHash, req.Password); err != nil {
+		recordLoginAttempt(r, req.Email, false)
+		recordAuthEvent(r, user.ID, constants.AuditActionLoginFailed, models.AuditState{"method": "password"})
 		utils.SendErrorResponse(w, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
+	if user.IsSuspended() {
+		utils.SendErrorResponse(w, http.StatusForbidden, service.SuspensionMessage(user))
+		return
+	}
+
+	// 5. 开启了两步验证：返回挑战令牌，验证码通过后才签发访问令牌
+	// 此时不记录登录成功，避免密码泄露后通过反复登录重置验证码的失败计数
+	if user.TwoFactorEnabled {
+		challengeToken, err := utils.GenerateChallengeToken(user.ID)
+		if err != nil {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to generate token")
+			return
+		}
+		utils.SendSuccessResponse(w, TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		})
+		return
+	}
+	recordLoginAttempt(r, req.Email, true)
+	recordAuthEvent(r, user.ID, constants.AuditActionLogin, models.AuditState{"method": "password"})
 
-	// 5. 生成JWT token
-	token, err := utils.GenerateToken(user.ID)
+	// 6. 创建登录会话并签发令牌
+	tokens, err := service.IssueTokens(user.ID, sessionClientFromRequest(r))
 	if err != nil {
 		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to generate token")
 		return
 	}
 
-	// 6. 返回响应
-	response := AuthResponse{
-		Token: token,
-		User:  *user,
+	// 7. 返回响应
+	utils.SendSuccessResponse(w, newAuthResponse(tokens, user))
+}
+
+// checkPasswordAttempt 已登录用户验证当前密码（关闭两步验证、修改密码）前检查失败次数限制
+// 与登录共用退避和锁定计数，避免窃取访问令牌后暴力猜测密码；返回用户邮箱用于记录本次结果
+// 被限制或出错时已写入响应，返回 false
+func checkPasswordAttempt(w http.ResponseWriter, r *http.Request, userID int) (string, bool) {
+	user, err := service.GetUserByID(userID)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "User not found")
+		return "", false
 	}
-	utils.SendSuccessResponse(w, response)
+	retryAfter, err := service.CheckLoginAllowed(user.Email, utils.ClientIP(r))
+	if err != nil {
+		if err.Error() == "too many login attempts" {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			utils.SendErrorResponse(w, http.StatusTooManyRequests, "Too many failed attempts, please try again later")
+			return "", false
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to verify password")
+		return "", false
+	}
+	return user.Email, true
 }
 
+// recordLoginAttempt 记录登录尝试，记录失败不影响登录流程
+func recordLoginAttempt(r *http.Request, email string, success bool) {
+	requestID, _ := r.Context().Value("requestID").(string)
+	actor := service.Actor{IP: utils.ClientIP(r), RequestID: requestID}
+	if err := service.RecordLoginAttempt(actor, email, success); err != nil {
+		log.Printf("⚠️  Failed to record login attempt: %v", err)
+	}
+}
@@ -5,11 +5,16 @@ import (
 	"net/http"
 	"strings"
 
+	"backend/internal/constants"
+	"backend/internal/service"
 	"backend/pkg/utils"
+
+	"github.com/gorilla/mux"
 )
 
 // AuthMiddleware 认证中间件
-// 验证 JWT Token 并将 userID 放入 Context
+// 验证 JWT Token 及其所属会话，并将 userID、sessionID、role 放入 Context
+// 以 shp_ 开头的个人访问令牌只能访问用 Scope 声明过权限范围的路由
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// 1. 从请求头获取 Authorization
@@ -27,6 +32,12 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		}
 		tokenString := parts[1]
 
+		// 个人访问令牌
+		if strings.HasPrefix(tokenString, constants.PersonalAccessTokenPrefix) {
+			authenticatePersonalAccessToken(w, r, next, tokenString)
+			return
+		}
+
 		// 3. 验证 Token
 		claims, err := utils.ValidateToken(tokenString)
 		if err != nil {
@@ -34,10 +45,80 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		// 4. 将 userID 存入 Context，传递给后续的 handler
+		// 4. 验证会话未被注销（退出登录后令牌立即失效），同时更新会话的最近活动时间
+		if claims.SessionID == "" {
+			utils.SendErrorResponse(w, http.StatusUnauthorized, "Invalid or expired token")
+			return
+		}
+		active, err := service.ValidateSession(claims.SessionID)
+		if err != nil {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to verify session")
+			return
+		}
+		if !active {
+			utils.SendErrorResponse(w, http.StatusUnauthorized, "Session has been revoked")
+			return
+		}
+
+		// 5. 被封禁的用户不能继续访问（已签发的令牌同样失效，解封后可以继续使用）
+		user, err := service.GetUserByID(claims.UserID)
+		if err != nil {
+			utils.SendErrorResponse(w, http.StatusUnauthorized, "Invalid or expired token")
+			return
+		}
+		if user.IsSuspended() {
+			utils.SendErrorResponse(w, http.StatusForbidden, service.SuspensionMessage(user))
+			return
+		}
+
+		// 6. 将 userID、sessionID、role 存入 Context，传递给后续的 handler
+		role := claims.Role
+		if role == "" {
+			role = constants.RoleUser
+		}
 		ctx := context.WithValue(r.Context(), "userID", claims.UserID)
+		ctx = context.WithValue(ctx, "sessionID", claims.SessionID)
+		ctx = context.WithValue(ctx, "role", role)
 
-		// 5. 调用下一个 handler
+		// 7. 调用下一个 handler
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// authenticatePersonalAccessToken 验证个人访问令牌及其权限范围
+func authenticatePersonalAccessToken(w http.ResponseWriter, r *http.Request, next http.Handler, tokenString string) {
+	// 1. 检查路由是否允许使用个人访问令牌
+	scope, ok := requiredScope(mux.CurrentRoute(r))
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusForbidden, "Personal access tokens cannot access this endpoint")
+		return
+	}
+
+	// 2. 验证令牌
+	token, user, err := service.AuthenticatePersonalAccessToken(tokenString)
+	if err != nil {
+		if err.Error() == "invalid personal access token" {
+			utils.SendErrorResponse(w, http.StatusUnauthorized, "Invalid or expired token")
+			return
+		}
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to verify token")
+		return
+	}
+
+	// 3. 被封禁的用户不能使用令牌
+	if user.IsSuspended() {
+		utils.SendErrorResponse(w, http.StatusForbidden, service.SuspensionMessage(user))
+		return
+	}
+
+	// 4. 检查权限范围
+	if !token.HasScope(scope) {
+		utils.SendErrorResponse(w, http.StatusForbidden, "Token is missing required scope: "+scope)
+		return
+	}
+
+	// 5. 将 userID、role 存入 Context（没有会话）
+	ctx := context.WithValue(r.Context(), "userID", user.ID)
+	ctx = context.WithValue(ctx, "role", user.Role)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
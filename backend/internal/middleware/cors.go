@@ -2,17 +2,29 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
+
+	"backend/internal/config"
 )
 
 // CORSMiddleware CORS跨域中间件
 // 允许前端（localhost:3000）访问后端（localhost:8080）
+// 只有来自前端地址（APP_BASE_URL）的请求可以携带 Cookie（第三方登录的 state Cookie），
+// 浏览器不接受 "*" 与携带凭据同时使用
 func CORSMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// 设置 CORS 响应头
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		origin := r.Header.Get("Origin")
+		if origin != "" && origin == strings.TrimSuffix(config.AppConfig.AppBaseURL, "/") {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		}
+		w.Header().Add("Vary", "Origin")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
 		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 
 		// 处理预检请求（OPTIONS）
 		if r.Method == "OPTIONS" {
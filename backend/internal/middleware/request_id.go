@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"backend/internal/constants"
+	"backend/pkg/utils"
+)
+
+// RequestIDMiddleware 请求ID中间件
+// 请求直接来自可信代理（TRUSTED_PROXIES）时使用网关传入的 X-Request-ID，否则或格式不合法时生成新的ID
+// 不信任客户端直接传入的请求ID，避免伪造或复用其他请求的ID干扰审计日志的关联
+// 请求ID写入响应头，并以 requestID 放入 Context（审计日志会记录）
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 1. 获取或生成请求ID
+		var requestID string
+		if utils.FromTrustedProxy(r) {
+			requestID = r.Header.Get(constants.HeaderRequestID)
+		}
+		if !isValidRequestID(requestID) {
+			id, err := utils.GenerateRandomToken(16)
+			if err != nil {
+				utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to generate request ID")
+				return
+			}
+			requestID = id
+		}
+
+		// 2. 写入响应头和 Context
+		w.Header().Set(constants.HeaderRequestID, requestID)
+		ctx := context.WithValue(r.Context(), "requestID", requestID)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// isValidRequestID 判断传入的请求ID是否合法（只允许字母、数字、-、_、.，避免写入日志时被注入内容）
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > constants.MaxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
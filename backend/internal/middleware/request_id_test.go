@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/constants"
+	"backend/pkg/utils"
+)
+
+func TestRequestIDMiddlewareTrustsOnlyProxies(t *testing.T) {
+	if err := utils.SetTrustedProxies("10.0.0.1"); err != nil {
+		t.Fatalf("SetTrustedProxies: %v", err)
+	}
+	t.Cleanup(func() { _ = utils.SetTrustedProxies("") })
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		requestID  string
+		wantKept   bool
+	}{
+		{name: "from trusted proxy", remoteAddr: "10.0.0.1:4000", requestID: "gateway-123", wantKept: true},
+		{name: "from client", remoteAddr: "203.0.113.7:4000", requestID: "gateway-123", wantKept: false},
+		{name: "invalid id from trusted proxy", remoteAddr: "10.0.0.1:4000", requestID: "bad id\n", wantKept: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = r.Context().Value("requestID").(string)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/items", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set(constants.HeaderRequestID, tt.requestID)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got == "" || rec.Header().Get(constants.HeaderRequestID) != got {
+				t.Fatalf("request ID must be set in the context and response header, got %q", got)
+			}
+			if kept := got == tt.requestID; kept != tt.wantKept {
+				t.Fatalf("request ID = %q, kept = %v, want %v", got, kept, tt.wantKept)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"backend/pkg/utils"
+)
+
+// RequireRole 要求用户拥有指定角色之一的中间件
+// 必须放在 AuthMiddleware 之后使用，角色来自访问令牌
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// 1. 从 Context 中获取角色
+			role, ok := r.Context().Value("role").(string)
+			if !ok {
+				utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+				return
+			}
+
+			// 2. 检查角色
+			if !allowed[role] {
+				utils.SendErrorResponse(w, http.StatusForbidden, "You do not have permission to access this resource")
+				return
+			}
+
+			// 3. 调用下一个 handler
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/gorilla/mux"
+)
+
+// routeScopes 路由需要的个人访问令牌权限范围
+// 没有声明权限范围的路由只能使用登录令牌访问（例如修改密码、管理令牌本身）
+var routeScopes = map[*mux.Route]string{}
+
+// Scope 声明路由允许使用个人访问令牌访问，并指定需要的权限范围（在 InitRouter 中调用）
+func Scope(route *mux.Route, scope string) *mux.Route {
+	routeScopes[route] = scope
+	return route
+}
+
+// requiredScope 获取路由需要的权限范围，未声明时返回 false
+func requiredScope(route *mux.Route) (string, bool) {
+	if route == nil {
+		return "", false
+	}
+	scope, ok := routeScopes[route]
+	return scope, ok
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/constants"
+
+	"github.com/gorilla/mux"
+)
+
+func TestRequiredScope(t *testing.T) {
+	router := mux.NewRouter()
+	scoped := Scope(router.HandleFunc("/posts", func(http.ResponseWriter, *http.Request) {}), constants.ScopePostsRead)
+	unscoped := router.HandleFunc("/password", func(http.ResponseWriter, *http.Request) {})
+
+	tests := []struct {
+		name   string
+		route  *mux.Route
+		want   string
+		wantOK bool
+	}{
+		{name: "scoped route", route: scoped, want: constants.ScopePostsRead, wantOK: true},
+		{name: "route without scope", route: unscoped, want: "", wantOK: false},
+		{name: "no route", route: nil, want: "", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := requiredScope(tt.route)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("requiredScope = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPersonalAccessTokenRejectedOnRouteWithoutScope(t *testing.T) {
+	// 未声明权限范围的路由在验证令牌之前就拒绝个人访问令牌
+	called := false
+	router := mux.NewRouter()
+	router.Handle("/password", AuthMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		called = true
+	})))
+
+	req := httptest.NewRequest(http.MethodPut, "/password", nil)
+	req.Header.Set("Authorization", "Bearer "+constants.PersonalAccessTokenPrefix+"token")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if called {
+		t.Fatalf("handler must not be called")
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"backend/internal/constants"
+	"backend/internal/service"
+	"backend/pkg/utils"
+)
+
+// RequireVerifiedEmail 要求邮箱已验证的中间件
+// 必须放在 AuthMiddleware 之后使用；未验证的用户仍可浏览，但不能发布商品或发送消息
+func RequireVerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 1. 从 Context 中获取用户ID
+		userID, ok := r.Context().Value("userID").(int)
+		if !ok {
+			utils.SendErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+			return
+		}
+
+		// 2. 查询邮箱是否已验证
+		verified, err := service.IsEmailVerified(userID)
+		if err != nil {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "Failed to verify user")
+			return
+		}
+		if !verified {
+			utils.SendErrorResponse(w, http.StatusForbidden, constants.ErrEmailNotVerified)
+			return
+		}
+
+		// 3. 调用下一个 handler
+		next.ServeHTTP(w, r)
+	})
+}
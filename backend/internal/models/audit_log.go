@@ -0,0 +1,60 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuditLog 审计日志（只能追加，数据库触发器禁止修改和删除）
+type AuditLog struct {
+	ID         int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	ActorID    *int       `json:"actor_id" gorm:"index"`                                             // 操作者ID，未登录（如登录失败）时为 null
+	ActorRole  string     `json:"actor_role" gorm:"size:20"`                                         // 操作时操作者的角色
+	Action     string     `json:"action" gorm:"not null;size:50;index"`                              // 操作，如 post.status_change、auth.login
+	TargetType string     `json:"target_type" gorm:"size:20;index:idx_audit_logs_target,priority:1"` // 操作对象类型：user, post, session
+	TargetID   string     `json:"target_id" gorm:"size:64;index:idx_audit_logs_target,priority:2"`   // 操作对象ID
+	Before     AuditState `json:"before" gorm:"type:jsonb"`                                          // 修改前的字段（只包含发生变化的字段）
+	After      AuditState `json:"after" gorm:"type:jsonb"`                                           // 修改后的字段
+	IP         string     `json:"ip" gorm:"size:64"`
+	RequestID  string     `json:"request_id" gorm:"size:64;index"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditState 审计日志中记录的字段值，以 JSON 保存
+type AuditState map[string]interface{}
+
+// Value 实现 driver.Valuer，写入数据库时序列化为 JSON
+func (s AuditState) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 实现 sql.Scanner，从数据库读取 JSON
+func (s *AuditState) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported audit state type %T", value)
+	}
+	return json.Unmarshal(b, s)
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// BackupCode 两步验证备用码（只保存哈希值，每个只能使用一次）
+type BackupCode struct {
+	ID        int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int        `json:"user_id" gorm:"not null;index"`
+	CodeHash  string     `json:"-" gorm:"not null;size:64"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (BackupCode) TableName() string {
+	return "backup_codes"
+}
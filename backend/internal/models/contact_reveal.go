@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ContactReveal 查看商品联系方式的记录（每个用户对每个商品只记录第一次查看）
+type ContactReveal struct {
+	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	PostID    int       `json:"post_id" gorm:"not null;uniqueIndex:idx_contact_reveals_post_user"`
+	UserID    int       `json:"user_id" gorm:"not null;uniqueIndex:idx_contact_reveals_post_user;index"` // 查看者ID
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index"`                                  // 查看时间
+}
+
+// TableName 指定表名
+func (ContactReveal) TableName() string {
+	return "contact_reveals"
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// EmailOutbox 邮件发件箱（与触发邮件的业务操作在同一个数据库事务中写入，由后台任务投递）
+type EmailOutbox struct {
+	ID            int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	ToAddress     string     `json:"to_address" gorm:"not null;size:100"`
+	Template      string     `json:"template" gorm:"not null;size:50"`
+	Subject       string     `json:"subject" gorm:"not null;size:255"`
+	TextBody      string     `json:"text_body" gorm:"type:text"`
+	HTMLBody      string     `json:"html_body" gorm:"type:text"`
+	Status        string     `json:"status" gorm:"not null;default:'pending';size:20;index:idx_email_outbox_pending,priority:1"` // pending, sent, failed
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"not null;index:idx_email_outbox_pending,priority:2"`
+	LastError     string     `json:"last_error" gorm:"type:text"`
+	LockedUntil   *time.Time `json:"locked_until"` // 被投递任务领取后的租约到期时间，到期未完成（如进程退出）时可被重新领取
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (EmailOutbox) TableName() string {
+	return "email_outbox"
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Favorite 收藏模型（用户收藏的商品）
+type Favorite struct {
+	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int       `json:"user_id" gorm:"not null;uniqueIndex:idx_favorites_user_post"`
+	PostID    int       `json:"post_id" gorm:"not null;uniqueIndex:idx_favorites_user_post;index"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// 关联
+	Post Post `json:"post" gorm:"foreignKey:PostID"`
+}
+
+// TableName 指定表名
+func (Favorite) TableName() string {
+	return "favorites"
+}
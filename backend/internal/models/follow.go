@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Follow 关注记录（用户关注的卖家）
+type Follow struct {
+	ID         int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	FollowerID int       `json:"follower_id" gorm:"not null;uniqueIndex:idx_follows_follower_seller"`
+	SellerID   int       `json:"seller_id" gorm:"not null;uniqueIndex:idx_follows_follower_seller;index"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (Follow) TableName() string {
+	return "follows"
+}
+
+// FeedItem 关注动态（卖家发布新商品时为每个关注者写入一条，用于未读标记）
+type FeedItem struct {
+	ID        int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int        `json:"user_id" gorm:"not null;uniqueIndex:idx_feed_items_user_post"` // 关注者
+	PostID    int        `json:"post_id" gorm:"not null;uniqueIndex:idx_feed_items_user_post;index"`
+	SellerID  int        `json:"seller_id" gorm:"not null;index"`
+	ReadAt    *time.Time `json:"read_at"` // 已读时间，未读为 null
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+
+	// 关联
+	Post Post `json:"post" gorm:"foreignKey:PostID"`
+}
+
+// TableName 指定表名
+func (FeedItem) TableName() string {
+	return "feed_items"
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// LoginAttempt 登录尝试记录（用于限制暴力破解）
+type LoginAttempt struct {
+	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Email     string    `json:"email" gorm:"not null;size:100;index:idx_login_attempts_email_time,priority:1"`
+	IP        string    `json:"ip" gorm:"not null;size:64;index:idx_login_attempts_ip_time,priority:1"`
+	Success   bool      `json:"success" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index:idx_login_attempts_email_time,priority:2;index:idx_login_attempts_ip_time,priority:2"`
+}
+
+// TableName 指定表名
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// LoginLockout 登录锁定事件（失败次数过多时生成，供管理员查看）
+type LoginLockout struct {
+	ID          int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Scope       string    `json:"scope" gorm:"not null;size:10"`         // 锁定范围：email 或 ip
+	Target      string    `json:"target" gorm:"not null;size:100;index"` // 被锁定的邮箱或IP
+	IP          string    `json:"ip" gorm:"size:64"`                     // 触发锁定的请求IP
+	Failures    int64     `json:"failures" gorm:"not null"`              // 窗口内的失败次数
+	LockedUntil time.Time `json:"locked_until" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// TableName 指定表名
+func (LoginLockout) TableName() string {
+	return "login_lockouts"
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Notification 站内通知模型
+type Notification struct {
+	ID        int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int        `json:"user_id" gorm:"not null;index"` // 接收者
+	Type      string     `json:"type" gorm:"not null;size:30"`  // 通知类型，例如 price_drop
+	Title     string     `json:"title" gorm:"not null;size:200"`
+	Body      string     `json:"body" gorm:"type:text"`
+	PostID    *int       `json:"post_id,omitempty"` // 相关商品（可选）
+	ReadAt    *time.Time `json:"read_at"`           // 已读时间，未读为 null
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// TableName 指定表名
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// NotificationPreference 用户的通知偏好（没有记录的类型默认接收）
+type NotificationPreference struct {
+	UserID       int       `json:"-" gorm:"primaryKey;autoIncrement:false"`
+	Type         string    `json:"type" gorm:"primaryKey;size:30"`
+	Enabled      bool      `json:"enabled" gorm:"not null"`                    // 是否接收（关闭后站内和邮件都不发送）
+	EmailEnabled bool      `json:"email_enabled" gorm:"not null;default:true"` // 是否同时发送邮件
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PersonalAccessToken 个人访问令牌（用于脚本调用 API，只保存哈希值）
+type PersonalAccessToken struct {
+	ID          int            `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID      int            `json:"user_id" gorm:"not null;index"`
+	Name        string         `json:"name" gorm:"not null;size:100"`
+	TokenPrefix string         `json:"token_prefix" gorm:"not null;size:16"` // 令牌开头几位，便于用户辨认
+	TokenHash   string         `json:"-" gorm:"not null;size:64;uniqueIndex"`
+	Scopes      pq.StringArray `json:"scopes" gorm:"type:text[];not null"` // 权限范围，例如 posts:read
+	ExpiresAt   *time.Time     `json:"expires_at"`                         // 过期时间
+	LastUsedAt  *time.Time     `json:"last_used_at"`                       // 最近使用时间
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// HasScope 判断令牌是否拥有某个权限范围
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -8,24 +8,33 @@ import (
 
 // Post 商品帖子模型
 type Post struct {
-	ID          int            `json:"id" gorm:"primaryKey;autoIncrement"`
-	UserID      int            `json:"user_id" gorm:"not null;index"`
-	Title       string         `json:"title" gorm:"not null;size:200"`
-	Description string         `json:"description" gorm:"type:text"`
-	Price       float64        `json:"price" gorm:"not null"`
-	ContactInfo string         `json:"contact_info" gorm:"not null;size:200"`
-	ZipCode     string         `json:"zip_code" gorm:"not null;size:20"`
-	Negotiable  bool           `json:"negotiable" gorm:"not null;default:false"`
-	ImageURLs   pq.StringArray `json:"image_urls" gorm:"type:text[]"`
-	Status      string         `json:"status" gorm:"default:'active';size:20"` // active, sold, deleted
-	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	ID           int            `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID       int            `json:"user_id" gorm:"not null;index"`
+	Title        string         `json:"title" gorm:"not null;size:200"`
+	Description  string         `json:"description" gorm:"type:text"`
+	Price        float64        `json:"price" gorm:"not null"`
+	ContactInfo  string         `json:"contact_info" gorm:"not null;size:200"`
+	ZipCode      string         `json:"zip_code" gorm:"not null;size:20"`
+	Category     string         `json:"category" gorm:"not null;size:20;default:'other';index"` // 分类，见 constants.PostCategory*
+	Negotiable   bool           `json:"negotiable" gorm:"not null;default:false"`
+	ImageURLs    pq.StringArray `json:"image_urls" gorm:"type:text[]"`
+	Status       string         `json:"status" gorm:"default:'active';size:20"` // active, sold, deleted, hidden
+	HiddenReason string         `json:"hidden_reason,omitempty" gorm:"size:20"` // 隐藏原因：moderation, suspension
+	SoldAt       *time.Time     `json:"sold_at,omitempty"`                      // 标记为已售出的时间（重新上架时清空）
+	CreatedAt    time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// 关联
-	User User `json:"user" gorm:"foreignKey:UserID"`
+	User         PostSeller     `json:"user" gorm:"foreignKey:UserID"`                    // 发布者的公开信息（不包含邮箱、封禁等信息）
+	PriceHistory []PriceHistory `json:"price_history,omitempty" gorm:"foreignKey:PostID"` // 价格变动记录（仅详情接口返回）
+
+	// 非数据库字段
+	IsFavorited   bool  `json:"is_favorited" gorm:"-"`   // 当前用户是否已收藏
+	FavoriteCount int64 `json:"favorite_count" gorm:"-"` // 收藏人数
+	ContactMasked bool  `json:"contact_masked" gorm:"-"` // 联系方式是否已隐藏（需通过 POST /item/{id}/contact 查看）
 }
 
 // TableName 指定表名
 func (Post) TableName() string {
 	return "posts"
-}
\ No newline at end of file
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// PriceHistory 商品价格变动记录
+type PriceHistory struct {
+	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	PostID    int       `json:"post_id" gorm:"not null;index"`
+	OldPrice  float64   `json:"old_price" gorm:"not null"`
+	NewPrice  float64   `json:"new_price" gorm:"not null"`
+	ChangedAt time.Time `json:"changed_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (PriceHistory) TableName() string {
+	return "post_price_history"
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Report 商品举报（每个用户对每个商品只能举报一次）
+type Report struct {
+	ID         int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	PostID     int       `json:"post_id" gorm:"not null;uniqueIndex:idx_reports_post_reporter"`
+	ReporterID int       `json:"reporter_id" gorm:"not null;uniqueIndex:idx_reports_post_reporter;index"`
+	Reason     string    `json:"reason" gorm:"not null;size:20"` // 举报原因：scam, prohibited, counterfeit, spam, offensive, other
+	Details    string    `json:"details" gorm:"type:text"`
+	Status     string    `json:"status" gorm:"not null;size:20;default:'pending';index"` // pending, dismissed, actioned
+	DecisionID *int      `json:"decision_id"`                                            // 处理该举报的审核决定
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (Report) TableName() string {
+	return "reports"
+}
+
+// ModerationDecision 审核决定（管理员对被举报商品的每次处理都会记录）
+type ModerationDecision struct {
+	ID          int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	PostID      int       `json:"post_id" gorm:"not null;index"`
+	ModeratorID int       `json:"moderator_id" gorm:"not null;index"`
+	Action      string    `json:"action" gorm:"not null;size:20"` // dismiss, hide_post, ban_user
+	Note        string    `json:"note" gorm:"type:text"`          // 处理说明（封禁时作为封禁原因）
+	ReportCount int       `json:"report_count" gorm:"not null"`   // 本次处理的待处理举报数量
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (ModerationDecision) TableName() string {
+	return "moderation_decisions"
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Review 交易评价模型（买卖双方各可对一笔交易评价一次）
+type Review struct {
+	ID            int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	TransactionID int       `json:"transaction_id" gorm:"not null;uniqueIndex:idx_reviews_transaction_reviewer"`
+	ReviewerID    int       `json:"reviewer_id" gorm:"not null;uniqueIndex:idx_reviews_transaction_reviewer"`
+	RevieweeID    int       `json:"reviewee_id" gorm:"not null;index"`
+	Role          string    `json:"role" gorm:"not null;size:10"` // 评价者在交易中的身份：buyer, seller
+	Rating        int       `json:"rating" gorm:"not null"`       // 1-5 分
+	Comment       string    `json:"comment" gorm:"type:text"`
+	PublishAt     time.Time `json:"publish_at" gorm:"not null;index"` // 公开时间：双方都评价后或评价窗口结束时
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (Review) TableName() string {
+	return "reviews"
+}
+
+// RatingSummary 评分汇总
+type RatingSummary struct {
+	Average float64 `json:"average"` // 平均分
+	Count   int64   `json:"count"`   // 评价数量
+}
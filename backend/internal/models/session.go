@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Session 登录会话（每次登录创建一个会话，会话内的刷新令牌每次使用后轮换）
+type Session struct {
+	ID         string     `json:"id" gorm:"primaryKey;size:64"`
+	UserID     int        `json:"user_id" gorm:"not null;index"`
+	UserAgent  string     `json:"user_agent" gorm:"size:255"`                             // 登录时的 User-Agent
+	IP         string     `json:"ip" gorm:"size:45"`                                      // 登录时的 IP
+	LastSeenAt time.Time  `json:"last_seen_at" gorm:"not null;default:CURRENT_TIMESTAMP"` // 最近活动时间（按间隔更新）
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`                             // 会话最长有效期
+	RevokedAt  *time.Time `json:"revoked_at"`                                             // 注销时间，未注销为 null
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+
+	// 非数据库字段
+	Current bool `json:"current" gorm:"-"` // 是否为发起请求的会话
+}
+
+// TableName 指定表名
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// RefreshToken 刷新令牌（只保存哈希值）
+type RefreshToken struct {
+	ID        int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	SessionID string     `json:"session_id" gorm:"not null;size:64;index"`
+	TokenHash string     `json:"-" gorm:"not null;size:64;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"` // 已轮换的时间，再次使用视为令牌泄露
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// SigningKey JWT 签名密钥（Ed25519，私钥加密后保存）
+// 最新的密钥用于签名；轮换后旧密钥仍用于验证，直到 ExpiresAt
+type SigningKey struct {
+	Kid                 string     `gorm:"primaryKey;size:64"`
+	Algorithm           string     `gorm:"not null;size:20"`
+	PublicKey           []byte     `gorm:"not null"`
+	EncryptedPrivateKey []byte     `gorm:"not null"` // 使用 JWT_SECRET 派生的密钥加密
+	ExpiresAt           *time.Time `gorm:"index"`    // 停止验证的时间，正在签名的密钥为 null
+	CreatedAt           time.Time  `gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (SigningKey) TableName() string {
+	return "signing_keys"
+}
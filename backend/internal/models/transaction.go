@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Transaction 交易记录（卖家将商品标记为已售出并指定买家时生成）
+type Transaction struct {
+	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	PostID    int       `json:"post_id" gorm:"not null;uniqueIndex"` // 每个商品只对应一笔交易
+	SellerID  int       `json:"seller_id" gorm:"not null;index"`
+	BuyerID   int       `json:"buyer_id" gorm:"not null;index"`
+	Price     float64   `json:"price" gorm:"not null"` // 成交时的价格
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// 关联
+	Post   Post       `json:"post" gorm:"foreignKey:PostID"`
+	Seller PostSeller `json:"seller" gorm:"foreignKey:SellerID"` // 只包含公开信息
+	Buyer  PostSeller `json:"buyer" gorm:"foreignKey:BuyerID"`   // 只包含公开信息
+}
+
+// TableName 指定表名
+func (Transaction) TableName() string {
+	return "transactions"
+}
@@ -4,15 +4,64 @@ import "time"
 
 // User 用户模型
 type User struct {
-	ID           int       `json:"id" gorm:"primaryKey;autoIncrement"`
-	Username     string    `json:"username" gorm:"unique;not null;size:50"`
-	Email        string    `json:"email" gorm:"unique;not null;size:100"`
-	PasswordHash string    `json:"-" gorm:"not null;size:255"` // 不返回给前端
-	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID            int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Username      string    `json:"username" gorm:"unique;not null;size:50"`
+	Email         string    `json:"email" gorm:"unique;not null;size:100"`
+	PasswordHash  string    `json:"-" gorm:"not null;size:255"` // 不返回给前端
+	EmailVerified bool      `json:"email_verified" gorm:"not null;default:false"`
+	Role          string    `json:"role" gorm:"not null;size:20;default:'user'"` // 角色：user, admin
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// 个人资料
+	DisplayName       string     `json:"display_name" gorm:"size:50"`
+	Bio               string     `json:"bio" gorm:"size:500"`
+	AvatarURL         string     `json:"avatar_url" gorm:"size:500"`
+	PreferredContact  string     `json:"preferred_contact" gorm:"size:20"` // 首选联系方式：email, phone, text
+	PendingEmail      string     `json:"-" gorm:"size:100"`                // 修改邮箱时待验证的新邮箱
+	UsernameChangedAt *time.Time `json:"-"`                                // 最近一次修改用户名的时间
+
+	// 账号删除
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"` // 计划删除时间（冷静期结束时间），取消后为 null
+	AnonymizedAt        *time.Time `json:"-" gorm:"index"`                  // 账号已删除（匿名化）的时间
+
+	// 封禁
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`                      // 封禁时间，未封禁为 null
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty" gorm:"index"`      // 封禁结束时间，永久封禁为 null
+	SuspensionReason string     `json:"suspension_reason,omitempty" gorm:"size:500"` // 封禁原因
+
+	// 两步验证
+	TwoFactorEnabled bool   `json:"two_factor_enabled" gorm:"not null;default:false"`
+	TOTPSecret       string `json:"-" gorm:"size:255"`           // TOTP 密钥（加密保存，启用前为待确认的密钥）
+	TOTPLastStep     int64  `json:"-" gorm:"not null;default:0"` // 最近一次使用的时间步，防止验证码重放
+
+	// 非数据库字段
+	SellerRating *RatingSummary `json:"seller_rating,omitempty" gorm:"-"` // 作为卖家收到的评分汇总
 }
 
 // TableName 指定表名
 func (User) TableName() string {
 	return "users"
-}
\ No newline at end of file
+}
+
+// PostSeller 商品发布者的公开信息（商品接口中返回，读取 users 表的部分字段）
+type PostSeller struct {
+	ID               int    `json:"id"`
+	Username         string `json:"username"`
+	DisplayName      string `json:"display_name"`
+	AvatarURL        string `json:"avatar_url"`
+	PreferredContact string `json:"-"` // 只在查看联系方式时返回
+
+	// 非数据库字段
+	SellerRating *RatingSummary `json:"rating,omitempty" gorm:"-"` // 作为卖家收到的评分汇总
+}
+
+// TableName 指定表名
+func (PostSeller) TableName() string {
+	return "users"
+}
+
+// IsSuspended 判断用户当前是否处于封禁状态（临时封禁到期后视为未封禁）
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil && (u.SuspendedUntil == nil || time.Now().Before(*u.SuspendedUntil))
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// UserBlock 屏蔽记录（被屏蔽的用户不能查看屏蔽者的联系方式，也看不到屏蔽者的商品）
+type UserBlock struct {
+	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	BlockerID int       `json:"blocker_id" gorm:"not null;uniqueIndex:idx_user_blocks_blocker_blocked"`
+	BlockedID int       `json:"blocked_id" gorm:"not null;uniqueIndex:idx_user_blocks_blocker_blocked;index"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (UserBlock) TableName() string {
+	return "user_blocks"
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// UserIdentity 用户绑定的外部登录身份（OIDC 提供方 + 用户在提供方的唯一标识）
+type UserIdentity struct {
+	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int       `json:"user_id" gorm:"not null;index"`
+	Issuer    string    `json:"issuer" gorm:"not null;size:255;uniqueIndex:idx_identity_issuer_subject"`
+	Subject   string    `json:"subject" gorm:"not null;size:255;uniqueIndex:idx_identity_issuer_subject"`
+	Email     string    `json:"email" gorm:"size:100"` // 绑定时提供方返回的邮箱
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// OIDCLoginState 进行中的 OIDC 登录（state 只保存哈希值，回调时一次性使用）
+type OIDCLoginState struct {
+	ID           int       `gorm:"primaryKey;autoIncrement"`
+	StateHash    string    `gorm:"not null;size:64;uniqueIndex"`
+	Nonce        string    `gorm:"not null;size:64"`
+	CodeVerifier string    `gorm:"not null;size:128"` // PKCE verifier
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
@@ -0,0 +1,199 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+// discoveryDocument OpenID Provider 配置（/.well-known/openid-configuration）中用到的字段
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider 外部 OIDC 身份提供方（授权码模式 + PKCE）
+type Provider struct {
+	issuer     string
+	oauth2     oauth2.Config
+	jwks       *keySet
+	httpClient *http.Client
+}
+
+// IDTokenClaims ID Token 中用到的声明
+type IDTokenClaims struct {
+	Nonce             string    `json:"nonce"`
+	Email             string    `json:"email"`
+	EmailVerified     boolClaim `json:"email_verified"`
+	Name              string    `json:"name"`
+	PreferredUsername string    `json:"preferred_username"`
+	jwt.RegisteredClaims
+}
+
+// NewProvider 通过 issuer 的发现文档创建身份提供方
+// issuer 可以是本地的模拟 OIDC 服务（允许 http），便于开发和测试
+func NewProvider(ctx context.Context, issuer, clientID, clientSecret, redirectURL string) (*Provider, error) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	// 1. 获取发现文档
+	wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	var doc discoveryDocument
+	if err := getJSON(ctx, httpClient, wellKnown, &doc); err != nil {
+		return nil, fmt.Errorf("failed to fetch discovery document: %w", err)
+	}
+
+	// 2. 发现文档中的 issuer 必须与配置一致，防止被其他服务冒充
+	if doc.Issuer != issuer {
+		return nil, fmt.Errorf("issuer mismatch: expected %q, got %q", issuer, doc.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("discovery document is missing required endpoints")
+	}
+
+	return &Provider{
+		issuer: issuer,
+		oauth2: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  doc.AuthorizationEndpoint,
+				TokenURL: doc.TokenEndpoint,
+			},
+			Scopes: []string{"openid", "email", "profile"},
+		},
+		jwks:       newKeySet(doc.JWKSURI, httpClient),
+		httpClient: httpClient,
+	}, nil
+}
+
+// Issuer 返回身份提供方的 issuer
+func (p *Provider) Issuer() string {
+	return p.issuer
+}
+
+// AuthCodeURL 生成授权地址（state 防 CSRF，nonce 绑定 ID Token，verifier 用于 PKCE S256）
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	return p.oauth2.AuthCodeURL(state,
+		oauth2.S256ChallengeOption(verifier),
+		oauth2.SetAuthURLParam("nonce", nonce),
+	)
+}
+
+// Exchange 用授权码换取令牌，并验证其中的 ID Token
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*IDTokenClaims, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.httpClient)
+
+	// 1. 用授权码和 PKCE verifier 换取令牌
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	// 2. 取出并验证 ID Token
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("token response does not contain an id_token")
+	}
+	return p.VerifyIDToken(ctx, rawIDToken, nonce)
+}
+
+// VerifyIDToken 验证 ID Token：签名（JWKS）、issuer、audience、过期时间和 nonce
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDTokenClaims, error) {
+	claims := &IDTokenClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.jwks.key(ctx, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(p.issuer),
+		jwt.WithAudience(p.oauth2.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	// 有多个 audience 时 azp 必须是本应用
+	if len(claims.Audience) > 1 {
+		var extra struct {
+			AuthorizedParty string `json:"azp"`
+		}
+		if err := decodeTokenPayload(rawIDToken, &extra); err != nil || extra.AuthorizedParty != p.oauth2.ClientID {
+			return nil, errors.New("invalid id_token: azp mismatch")
+		}
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("invalid id_token: missing subject")
+	}
+	if nonce == "" || claims.Nonce != nonce {
+		return nil, errors.New("invalid id_token: nonce mismatch")
+	}
+	return claims, nil
+}
+
+// boolClaim 兼容部分提供方把布尔值写成字符串（例如 "email_verified": "true"）
+type boolClaim bool
+
+// UnmarshalJSON 同时支持 true 和 "true"
+func (b *boolClaim) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch value := v.(type) {
+	case bool:
+		*b = boolClaim(value)
+	case string:
+		*b = boolClaim(value == "true")
+	default:
+		*b = false
+	}
+	return nil
+}
+
+// decodeTokenPayload 解码 JWT 的 payload（签名需已验证）
+func decodeTokenPayload(rawToken string, v interface{}) error {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return errors.New("malformed token")
+	}
+	payload, err := jwt.NewParser().DecodeSegment(parts[1])
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(payload, v)
+}
+
+// getJSON 发送 GET 请求并解析 JSON 响应
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("GET %s: %s: %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,260 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID     = "test-client"
+	testClientSecret = "test-secret"
+	testRedirectURL  = "http://localhost:3000/auth/callback"
+	testKeyID        = "test-key"
+)
+
+// authorization 模拟提供方记录的一次授权（用户同意后签发 code）
+type authorization struct {
+	challenge string
+	nonce     string
+}
+
+// mockIssuer 基于 httptest 的模拟 OIDC 提供方：发现文档、JWKS 和令牌接口
+type mockIssuer struct {
+	server *httptest.Server
+	key    *ecdsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]authorization
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	m := &mockIssuer{key: key, codes: make(map[string]authorization)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("/jwks", m.jwks)
+	mux.HandleFunc("/token", m.token)
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockIssuer) issuer() string {
+	return m.server.URL
+}
+
+func (m *mockIssuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 m.issuer(),
+		"authorization_endpoint": m.issuer() + "/authorize",
+		"token_endpoint":         m.issuer() + "/token",
+		"jwks_uri":               m.issuer() + "/jwks",
+	})
+}
+
+func (m *mockIssuer) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := m.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": testKeyID,
+			"kty": "EC",
+			"use": "sig",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, 32))),
+			"y":   base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, 32))),
+		}},
+	})
+}
+
+// authorize 模拟用户在提供方完成授权：解析授权地址中的参数并签发一次性 code
+func (m *mockIssuer) authorize(t *testing.T, authURL string) (code string, params url.Values) {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse authorization url: %v", err)
+	}
+	params = u.Query()
+	code = "code-" + params.Get("state")
+
+	m.mu.Lock()
+	m.codes[code] = authorization{challenge: params.Get("code_challenge"), nonce: params.Get("nonce")}
+	m.mu.Unlock()
+	return code, params
+}
+
+// token 令牌接口：校验 code 和 PKCE verifier，返回签名的 ID Token
+func (m *mockIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	m.mu.Lock()
+	auth, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != auth.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodES256, IDTokenClaims{
+		Nonce:         auth.nonce,
+		Email:         "alice@example.com",
+		EmailVerified: true,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer(),
+			Subject:   "alice",
+			Audience:  jwt.ClaimStrings{testClientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		},
+	})
+	idToken.Header["kid"] = testKeyID
+	signed, err := idToken.SignedString(m.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     signed,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestProvider(t *testing.T, m *mockIssuer) *Provider {
+	t.Helper()
+
+	provider, err := NewProvider(context.Background(), m.issuer(), testClientID, testClientSecret, testRedirectURL)
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	return provider
+}
+
+func TestAuthCodeURLCarriesStateNonceAndPKCEChallenge(t *testing.T) {
+	m := newMockIssuer(t)
+	provider := newTestProvider(t, m)
+
+	authURL := provider.AuthCodeURL("state-1", "nonce-1", "verifier-1")
+	if !strings.HasPrefix(authURL, m.issuer()+"/authorize?") {
+		t.Fatalf("unexpected authorization endpoint: %s", authURL)
+	}
+
+	_, params := m.authorize(t, authURL)
+	sum := sha256.Sum256([]byte("verifier-1"))
+	want := map[string]string{
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"client_id":             testClientID,
+		"redirect_uri":          testRedirectURL,
+		"response_type":         "code",
+		"code_challenge_method": "S256",
+		"code_challenge":        base64.RawURLEncoding.EncodeToString(sum[:]),
+	}
+	for name, value := range want {
+		if got := params.Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+	if params.Get("code_verifier") != "" {
+		t.Errorf("verifier must not be sent in the authorization request")
+	}
+}
+
+func TestExchangeSucceeds(t *testing.T) {
+	m := newMockIssuer(t)
+	provider := newTestProvider(t, m)
+
+	code, _ := m.authorize(t, provider.AuthCodeURL("state-1", "nonce-1", "verifier-1"))
+	claims, err := provider.Exchange(context.Background(), code, "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if claims.Subject != "alice" || claims.Email != "alice@example.com" || !bool(claims.EmailVerified) {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestExchangeRejectsNonceMismatch(t *testing.T) {
+	m := newMockIssuer(t)
+	provider := newTestProvider(t, m)
+
+	// ID Token 中的 nonce 来自另一次登录（例如被重放的令牌）
+	code, _ := m.authorize(t, provider.AuthCodeURL("state-1", "nonce-other", "verifier-1"))
+	_, err := provider.Exchange(context.Background(), code, "verifier-1", "nonce-1")
+	if err == nil || !strings.Contains(err.Error(), "nonce mismatch") {
+		t.Fatalf("expected nonce mismatch, got %v", err)
+	}
+}
+
+func TestExchangeRejectsWrongPKCEVerifier(t *testing.T) {
+	m := newMockIssuer(t)
+	provider := newTestProvider(t, m)
+
+	// 截获 code 的攻击者没有 verifier，提供方拒绝换取令牌
+	code, _ := m.authorize(t, provider.AuthCodeURL("state-1", "nonce-1", "verifier-1"))
+	_, err := provider.Exchange(context.Background(), code, "verifier-attacker", "nonce-1")
+	if err == nil || !strings.Contains(err.Error(), "failed to exchange authorization code") {
+		t.Fatalf("expected exchange failure, got %v", err)
+	}
+}
+
+func TestExchangeRejectsReusedCode(t *testing.T) {
+	m := newMockIssuer(t)
+	provider := newTestProvider(t, m)
+
+	code, _ := m.authorize(t, provider.AuthCodeURL("state-1", "nonce-1", "verifier-1"))
+	if _, err := provider.Exchange(context.Background(), code, "verifier-1", "nonce-1"); err != nil {
+		t.Fatalf("first Exchange: %v", err)
+	}
+	if _, err := provider.Exchange(context.Background(), code, "verifier-1", "nonce-1"); err == nil {
+		t.Fatalf("expected reused code to be rejected")
+	}
+}
+
+func TestNewProviderRejectsIssuerMismatch(t *testing.T) {
+	m := newMockIssuer(t)
+
+	_, err := NewProvider(context.Background(), m.issuer()+"/other", testClientID, testClientSecret, testRedirectURL)
+	if err == nil {
+		t.Fatalf("expected discovery to fail for a different issuer")
+	}
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/email"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RequestAccountDeletion 申请删除账号
+// 账号在冷静期结束后才会被匿名化，冷静期内可以通过 CancelAccountDeletion 取消
+// 需要验证密码；第三方登录的用户也可以在刚登录后（sessionID 为当前会话）不输入密码
+func RequestAccountDeletion(userID int, sessionID string, password string) (*models.User, error) {
+	db := database.GetDB()
+
+	var user models.User
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 1. 查询用户并确认是本人操作
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+		if err := verifyReauthentication(tx, &user, sessionID, password); err != nil {
+			return err
+		}
+		if user.DeletionScheduledAt != nil {
+			return fmt.Errorf("account deletion already scheduled")
+		}
+
+		// 2. 设置计划删除时间
+		scheduledAt := time.Now().AddDate(0, 0, constants.AccountDeletionCoolingOffDays)
+		if err := tx.Model(&user).Update("deletion_scheduled_at", scheduledAt).Error; err != nil {
+			return err
+		}
+
+		// 3. 发送提醒邮件（防止他人盗用账号后删除）
+		return EnqueueEmail(tx, user.Email, email.TemplateAccountDeletion, map[string]interface{}{
+			"Username":    user.Username,
+			"ScheduledAt": scheduledAt.Format("January 2, 2006"),
+			"Link":        config.AppConfig.AppBaseURL + "/settings/account",
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// CancelAccountDeletion 在冷静期内取消删除账号
+func CancelAccountDeletion(userID int) error {
+	db := database.GetDB()
+
+	result := db.Model(&models.User{}).
+		Where("id = ? AND deletion_scheduled_at IS NOT NULL AND anonymized_at IS NULL", userID).
+		Update("deletion_scheduled_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("account deletion not scheduled")
+	}
+	return nil
+}
+
+// StartAccountDeletionWorker 定期删除冷静期已结束的账号，直到 ctx 结束
+func StartAccountDeletionWorker(ctx context.Context) {
+	ticker := time.NewTicker(constants.AccountDeletionWorkerIntervalMinutes * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := deleteDueAccounts(); err != nil {
+				log.Printf("⚠️  Account deletion worker error: %v", err)
+			}
+		}
+	}
+}
+
+// deleteDueAccounts 匿名化一批冷静期已结束的账号，并删除它们上传的文件
+// 使用 FOR UPDATE SKIP LOCKED，多个实例同时运行时不会重复处理
+func deleteDueAccounts() error {
+	db := database.GetDB()
+
+	var filenames []string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 1. 锁定一批到期的账号
+		var users []models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("deletion_scheduled_at <= ? AND anonymized_at IS NULL", time.Now()).
+			Order("deletion_scheduled_at ASC").
+			Limit(constants.AccountDeletionBatchSize).
+			Find(&users).Error; err != nil {
+			return err
+		}
+
+		// 2. 逐个匿名化
+		for i := range users {
+			files, err := anonymizeUser(tx, &users[i])
+			if err != nil {
+				return fmt.Errorf("failed to delete account %d: %w", users[i].ID, err)
+			}
+			filenames = append(filenames, files...)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 3. 事务提交后删除文件（失败只记录日志）
+	for _, filename := range filenames {
+		if err := database.DeleteFile(filename); err != nil {
+			log.Printf("⚠️  Failed to delete file %s of deleted account: %v", filename, err)
+		}
+	}
+	return nil
+}
+
+// anonymizeUser 匿名化用户（需在数据库事务中调用）
+// 保留交易和评价记录（对方仍需要查看），删除商品、登录凭据和其它个人数据，返回需要删除的文件名
+func anonymizeUser(tx *gorm.DB, user *models.User) ([]string, error) {
+	// 1. 收集头像和商品图片
+	var filenames []string
+	if filename, ok := database.FileNameFromURL(user.AvatarURL); ok {
+		filenames = append(filenames, filename)
+	}
+	var posts []models.Post
+	if err := tx.Where("user_id = ?", user.ID).Find(&posts).Error; err != nil {
+		return nil, err
+	}
+	for _, post := range posts {
+		for _, url := range post.ImageURLs {
+			if filename, ok := database.FileNameFromURL(url); ok {
+				filenames = append(filenames, filename)
+			}
+		}
+	}
+
+	// 2. 软删除商品并清空联系方式和图片
+	if err := tx.Model(&models.Post{}).Where("user_id = ?", user.ID).Updates(map[string]interface{}{
+		"status":       constants.PostStatusDeleted,
+		"contact_info": "",
+		"image_urls":   nil,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	// 3. 注销会话，删除登录凭据和其它个人数据
+	if err := revokeSessionsExcept(tx, user.ID, ""); err != nil {
+		return nil, err
+	}
+	for _, model := range []interface{}{
+		&models.PersonalAccessToken{},
+		&models.UserIdentity{},
+		&models.BackupCode{},
+		&models.UserToken{},
+		&models.Favorite{},
+		&models.Notification{},
+		&models.NotificationPreference{},
+		&models.ContactReveal{},
+	} {
+		if err := tx.Where("user_id = ?", user.ID).Delete(model).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Where("blocker_id = ? OR blocked_id = ?", user.ID, user.ID).Delete(&models.UserBlock{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("follower_id = ? OR seller_id = ?", user.ID, user.ID).Delete(&models.Follow{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("user_id = ? OR seller_id = ?", user.ID, user.ID).Delete(&models.FeedItem{}).Error; err != nil {
+		return nil, err
+	}
+
+	// 4. 匿名化用户信息（密码替换为随机值，无法再登录）
+	randomPassword, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	passwordHash, err := utils.HashPassword(randomPassword)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Model(user).Updates(map[string]interface{}{
+		"username":              fmt.Sprintf("deleted-user-%d", user.ID),
+		"email":                 fmt.Sprintf("deleted-user-%d@deleted.invalid", user.ID),
+		"password_hash":         passwordHash,
+		"email_verified":        false,
+		"role":                  constants.RoleUser,
+		"display_name":          "",
+		"bio":                   "",
+		"avatar_url":            "",
+		"preferred_contact":     "",
+		"pending_email":         "",
+		"two_factor_enabled":    false,
+		"totp_secret":           "",
+		"deletion_scheduled_at": nil,
+		"anonymized_at":         time.Now(),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return filenames, nil
+}
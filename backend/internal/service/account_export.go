@@ -0,0 +1,192 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"time"
+
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// exportTransaction 导出的交易记录（对方只包含用户名）
+type exportTransaction struct {
+	ID                   int       `json:"id"`
+	PostID               int       `json:"post_id"`
+	PostTitle            string    `json:"post_title"`
+	Role                 string    `json:"role"` // 我在交易中的身份：buyer, seller
+	CounterpartyUsername string    `json:"counterparty_username"`
+	Price                float64   `json:"price"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+// exportReview 导出的评价记录
+type exportReview struct {
+	ID            int       `json:"id"`
+	TransactionID int       `json:"transaction_id"`
+	Direction     string    `json:"direction"` // written: 我写的评价，received: 我收到的评价
+	Role          string    `json:"role"`      // 评价者在交易中的身份
+	Rating        int       `json:"rating"`
+	Comment       string    `json:"comment"`
+	PublishAt     time.Time `json:"publish_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AccountExport 用户的个人数据导出
+// 包含个人资料、商品（含价格变动记录和图片）、交易、评价、收藏、站内通知和登录会话
+// 目前没有私信功能（没有消息表），因此不包含私信；增加私信后需要加入导出
+type AccountExport struct {
+	userID        int
+	profile       *ProfileResponse
+	posts         []models.Post
+	transactions  []exportTransaction
+	reviews       []exportReview
+	favorites     []models.Favorite
+	notifications []models.Notification
+	sessions      []models.Session
+}
+
+// GetAccountExport 查询用户的所有个人数据（文件在写入 ZIP 时才读取）
+func GetAccountExport(userID int) (*AccountExport, error) {
+	db := database.GetDB()
+
+	// 1. 个人资料和商品
+	profile, err := GetProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var posts []models.Post
+	if err := db.Preload("PriceHistory", func(db *gorm.DB) *gorm.DB {
+		return db.Order("changed_at ASC")
+	}).Where("user_id = ?", userID).Order("created_at ASC").Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	// 2. 交易和评价（对方只导出用户名）
+	transactions := []exportTransaction{}
+	if err := db.Table("transactions").
+		Joins("JOIN posts ON posts.id = transactions.post_id").
+		Joins("JOIN users ON users.id = CASE WHEN transactions.seller_id = ? THEN transactions.buyer_id ELSE transactions.seller_id END", userID).
+		Where("transactions.buyer_id = ? OR transactions.seller_id = ?", userID, userID).
+		Select("transactions.id, transactions.post_id, posts.title AS post_title, "+
+			"CASE WHEN transactions.seller_id = ? THEN 'seller' ELSE 'buyer' END AS role, "+
+			"users.username AS counterparty_username, transactions.price, transactions.created_at", userID).
+		Order("transactions.created_at ASC").
+		Scan(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	reviews := []exportReview{}
+	if err := db.Model(&models.Review{}).
+		Where("reviewer_id = ? OR (reviewee_id = ? AND publish_at <= ?)", userID, userID, time.Now()).
+		Select("id, transaction_id, CASE WHEN reviewer_id = ? THEN 'written' ELSE 'received' END AS direction, "+
+			"role, rating, comment, publish_at, created_at", userID).
+		Order("created_at ASC").
+		Scan(&reviews).Error; err != nil {
+		return nil, err
+	}
+
+	// 3. 收藏、站内通知和登录会话
+	var favorites []models.Favorite
+	if err := db.Where("user_id = ?", userID).Order("created_at ASC").Find(&favorites).Error; err != nil {
+		return nil, err
+	}
+
+	var notifications []models.Notification
+	if err := db.Where("user_id = ?", userID).Order("created_at ASC").Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+
+	var sessions []models.Session
+	if err := db.Where("user_id = ?", userID).Order("created_at ASC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	return &AccountExport{
+		userID:        userID,
+		profile:       profile,
+		posts:         posts,
+		transactions:  transactions,
+		reviews:       reviews,
+		favorites:     favorites,
+		notifications: notifications,
+		sessions:      sessions,
+	}, nil
+}
+
+// WriteZip 将导出的数据打包为 ZIP 写入 w（数据文件为 JSON，图片放在 images 目录）
+func (e *AccountExport) WriteZip(ctx context.Context, w io.Writer) error {
+	// 1. 写入 JSON 文件
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", e.profile},
+		{"posts.json", e.posts},
+		{"transactions.json", e.transactions},
+		{"reviews.json", e.reviews},
+		{"favorites.json", e.favorites},
+		{"notifications.json", e.notifications},
+		{"sessions.json", e.sessions},
+	}
+	for _, file := range files {
+		if err := writeZipJSON(zw, file.name, file.data); err != nil {
+			return err
+		}
+	}
+
+	// 2. 写入头像和商品图片（读取失败的文件跳过，只记录日志）
+	urls := []string{e.profile.AvatarURL}
+	for _, post := range e.posts {
+		urls = append(urls, post.ImageURLs...)
+	}
+	for _, url := range urls {
+		filename, ok := database.FileNameFromURL(url)
+		if !ok {
+			continue
+		}
+		if err := writeZipBlob(ctx, zw, filename); err != nil {
+			log.Printf("⚠️  Failed to export file %s for user %d: %v", filename, e.userID, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// writeZipJSON 将数据以 JSON 格式写入 ZIP
+func writeZipJSON(zw *zip.Writer, name string, data interface{}) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// writeZipBlob 将 GCS 中的文件写入 ZIP 的 images 目录
+func writeZipBlob(ctx context.Context, zw *zip.Writer, filename string) error {
+	reader, err := database.OpenFile(ctx, filename)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	f, err := zw.Create(path.Join("images", path.Base(filename)))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, reader); err != nil {
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// recordAudit 写入一条审计日志（需在修改数据的同一事务中调用，保证日志和修改同时提交）
+// before 和 after 中值相同的字段不会记录；actor.UserID 为 0 表示系统操作（如定时任务）
+func recordAudit(tx *gorm.DB, actor Actor, action string, targetType string, targetID interface{}, before, after models.AuditState) error {
+	// 1. 只保留发生变化的字段
+	for key, value := range after {
+		if old, ok := before[key]; ok && reflect.DeepEqual(old, value) {
+			delete(before, key)
+			delete(after, key)
+		}
+	}
+
+	// 2. 保存日志
+	entry := models.AuditLog{
+		ActorRole:  actor.Role,
+		Action:     action,
+		TargetType: targetType,
+		Before:     before,
+		After:      after,
+		IP:         actor.IP,
+		RequestID:  actor.RequestID,
+	}
+	if actor.UserID != 0 {
+		entry.ActorID = &actor.UserID
+	}
+	if targetID != nil {
+		entry.TargetID = fmt.Sprint(targetID)
+	}
+	return tx.Create(&entry).Error
+}
+
+// RecordAuthEvent 记录登录、退出、修改密码等认证事件（操作对象为操作者本人）
+// 登录失败时 actor.UserID 可以为 0，details 中记录尝试登录的邮箱的哈希（见 AuditEmailHash）
+func RecordAuthEvent(actor Actor, action string, details models.AuditState) error {
+	db := database.GetDB()
+
+	var targetID interface{}
+	if actor.UserID != 0 {
+		targetID = actor.UserID
+	}
+	return recordAudit(db, actor, action, constants.AuditTargetUser, targetID, nil, details)
+}
+
+// AuditEmailHash 计算邮箱的哈希，用于在审计日志中代替邮箱明文
+// 审计日志不可修改，账号删除后也不会被匿名化，因此不保存邮箱明文；调查时可以对已知邮箱计算哈希后比对
+func AuditEmailHash(email string) string {
+	return utils.HashToken(strings.ToLower(strings.TrimSpace(email)))
+}
+
+// GetAuditLogsRequest 查询审计日志请求参数（筛选条件为空时不筛选）
+type GetAuditLogsRequest struct {
+	Actor      Actor      // 操作者（需要查看审计日志的权限）
+	ActorID    int        // 按操作者筛选
+	Action     string     // 按操作筛选
+	TargetType string     // 按操作对象类型筛选
+	TargetID   string     // 按操作对象ID筛选
+	RequestID  string     // 按请求ID筛选
+	From       *time.Time // 开始时间（包含）
+	To         *time.Time // 结束时间（不包含）
+	Page       int        // 页码，从1开始
+	PageSize   int        // 每页数量
+}
+
+// GetAuditLogsResponse 查询审计日志响应
+type GetAuditLogsResponse struct {
+	Logs       []models.AuditLog `json:"logs"`
+	TotalCount int64             `json:"total_count"` // 总数量
+	Page       int               `json:"page"`        // 当前页码
+	PageSize   int               `json:"page_size"`   // 每页数量
+	TotalPages int               `json:"total_pages"` // 总页数
+}
+
+// GetAuditLogs 查询审计日志（分页，最新的在前）
+func GetAuditLogs(req GetAuditLogsRequest) (*GetAuditLogsResponse, error) {
+	db := database.GetDB()
+
+	// 1. 验证权限
+	if !req.Actor.Can(constants.PermissionViewAuditLog) {
+		return nil, fmt.Errorf("unauthorized: you cannot view audit logs")
+	}
+
+	// 2. 设置默认值
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 50
+	}
+
+	// 3. 构建查询
+	baseQuery := func() *gorm.DB {
+		query := db.Model(&models.AuditLog{})
+		if req.ActorID != 0 {
+			query = query.Where("actor_id = ?", req.ActorID)
+		}
+		if req.Action != "" {
+			query = query.Where("action = ?", req.Action)
+		}
+		if req.TargetType != "" {
+			query = query.Where("target_type = ?", req.TargetType)
+		}
+		if req.TargetID != "" {
+			query = query.Where("target_id = ?", req.TargetID)
+		}
+		if req.RequestID != "" {
+			query = query.Where("request_id = ?", req.RequestID)
+		}
+		if req.From != nil {
+			query = query.Where("created_at >= ?", *req.From)
+		}
+		if req.To != nil {
+			query = query.Where("created_at < ?", *req.To)
+		}
+		return query
+	}
+
+	// 4. 查询总数量
+	var totalCount int64
+	if err := baseQuery().Count(&totalCount).Error; err != nil {
+		return nil, err
+	}
+
+	// 5. 查询分页数据
+	logs := []models.AuditLog{}
+	if err := baseQuery().
+		Order("id DESC").
+		Limit(req.PageSize).
+		Offset((req.Page - 1) * req.PageSize).
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	return &GetAuditLogsResponse{
+		Logs:       logs,
+		TotalCount: totalCount,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: calcTotalPages(totalCount, req.PageSize),
+	}, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BlockUser 屏蔽用户（重复屏蔽不会报错）
+func BlockUser(blockerID int, blockedID int) error {
+	db := database.GetDB()
+
+	// 1. 不能屏蔽自己
+	if blockerID == blockedID {
+		return fmt.Errorf("you cannot block yourself")
+	}
+
+	// 2. 确认用户存在且未被删除
+	var user models.User
+	if err := db.Where("id = ? AND anonymized_at IS NULL", blockedID).First(&user).Error; err != nil {
+		return err
+	}
+
+	// 3. 保存屏蔽记录（已屏蔽时忽略），并取消被屏蔽用户对屏蔽者的关注
+	return db.Transaction(func(tx *gorm.DB) error {
+		block := models.UserBlock{
+			BlockerID: blockerID,
+			BlockedID: blockedID,
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&block).Error; err != nil {
+			return err
+		}
+		return removeFollow(tx, blockedID, blockerID)
+	})
+}
+
+// UnblockUser 取消屏蔽（未屏蔽时不会报错）
+func UnblockUser(blockerID int, blockedID int) error {
+	db := database.GetDB()
+	return db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&models.UserBlock{}).Error
+}
+
+// IsBlocked 判断 blockerID 是否屏蔽了 blockedID
+func IsBlocked(blockerID int, blockedID int) (bool, error) {
+	db := database.GetDB()
+
+	var count int64
+	if err := db.Model(&models.UserBlock{}).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetBlockedUsersRequest 获取屏蔽列表请求参数
+type GetBlockedUsersRequest struct {
+	UserID   int // 用户ID
+	Page     int // 页码，从1开始
+	PageSize int // 每页数量
+}
+
+// BlockedUserItem 屏蔽列表中的单个用户（只包含公开信息）
+type BlockedUserItem struct {
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	BlockedAt time.Time `json:"blocked_at"`
+}
+
+// GetBlockedUsersResponse 获取屏蔽列表响应
+type GetBlockedUsersResponse struct {
+	Users      []BlockedUserItem `json:"users"`
+	TotalCount int64             `json:"total_count"` // 总数量
+	Page       int               `json:"page"`        // 当前页码
+	PageSize   int               `json:"page_size"`   // 每页数量
+	TotalPages int               `json:"total_pages"` // 总页数
+}
+
+// GetBlockedUsers 获取我屏蔽的用户（分页）
+func GetBlockedUsers(req GetBlockedUsersRequest) (*GetBlockedUsersResponse, error) {
+	db := database.GetDB()
+
+	// 1. 设置默认值
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 20
+	}
+
+	// 2. 构建查询
+	baseQuery := func() *gorm.DB {
+		return db.Table("user_blocks").
+			Joins("JOIN users ON users.id = user_blocks.blocked_id").
+			Where("user_blocks.blocker_id = ?", req.UserID)
+	}
+
+	// 3. 查询总数量
+	var totalCount int64
+	if err := baseQuery().Count(&totalCount).Error; err != nil {
+		return nil, err
+	}
+
+	// 4. 查询分页数据
+	users := []BlockedUserItem{}
+	if err := baseQuery().
+		Select("user_blocks.blocked_id AS user_id, users.username, user_blocks.created_at AS blocked_at").
+		Order("user_blocks.created_at DESC").
+		Limit(req.PageSize).
+		Offset((req.Page - 1) * req.PageSize).
+		Scan(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return &GetBlockedUsersResponse{
+		Users:      users,
+		TotalCount: totalCount,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: calcTotalPages(totalCount, req.PageSize),
+	}, nil
+}
+
+// excludeBlockedSellers 过滤掉屏蔽了当前用户的卖家发布的商品（用于商品列表查询）
+func excludeBlockedSellers(query *gorm.DB, viewerID int) *gorm.DB {
+	return query.Where("posts.user_id NOT IN (?)",
+		database.GetDB().Model(&models.UserBlock{}).Select("blocker_id").Where("blocked_id = ?", viewerID))
+}
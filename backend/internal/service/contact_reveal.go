@@ -0,0 +1,245 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ContactInfoResponse 查看联系方式的响应
+type ContactInfoResponse struct {
+	PostID           int    `json:"post_id"`
+	ContactInfo      string `json:"contact_info"`
+	PreferredContact string `json:"preferred_contact"` // 卖家的首选联系方式
+}
+
+// RevealContactInfo 查看商品的完整联系方式，并记录查看者和查看时间
+// 卖家查看自己的商品、或者之前已经查看过的商品不计入每日次数限制；被卖家屏蔽的用户不能查看
+func RevealContactInfo(postID int, viewerID int) (*ContactInfoResponse, error) {
+	db := database.GetDB()
+
+	// 1. 查询商品（已删除的商品不能查看，已隐藏的商品只有卖家本人可以查看）
+	var post models.Post
+	if err := db.Preload("User").Where("id = ? AND status != ?", postID, constants.PostStatusDeleted).
+		First(&post).Error; err != nil {
+		return nil, err
+	}
+	// 被管理员隐藏的商品只有卖家本人可以查看
+	if post.Status == constants.PostStatusHidden && post.UserID != viewerID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	resp := &ContactInfoResponse{
+		PostID:           post.ID,
+		ContactInfo:      post.ContactInfo,
+		PreferredContact: post.User.PreferredContact,
+	}
+	if post.UserID == viewerID {
+		return resp, nil
+	}
+
+	// 2. 被卖家屏蔽的用户不能查看
+	blocked, err := IsBlocked(post.UserID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, fmt.Errorf("unauthorized: the seller has blocked you")
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// 3. 锁定查看者，避免并发请求绕过次数限制
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.User{}, viewerID).Error; err != nil {
+			return err
+		}
+
+		// 4. 之前查看过的商品直接返回
+		var count int64
+		if err := tx.Model(&models.ContactReveal{}).
+			Where("post_id = ? AND user_id = ?", post.ID, viewerID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		// 5. 检查 24 小时内的查看次数
+		if err := tx.Model(&models.ContactReveal{}).
+			Where("user_id = ? AND created_at > ?", viewerID, time.Now().Add(-24*time.Hour)).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= constants.MaxContactRevealsPerDay {
+			return fmt.Errorf("daily contact reveal limit reached")
+		}
+
+		// 6. 记录查看
+		return tx.Create(&models.ContactReveal{PostID: post.ID, UserID: viewerID}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetContactRevealsRequest 获取联系方式查看记录请求参数
+type GetContactRevealsRequest struct {
+	PostID   int   // 商品ID
+	Actor    Actor // 操作者（商品所有者或管理员）
+	Page     int   // 页码，从1开始
+	PageSize int   // 每页数量
+}
+
+// ContactRevealItem 查看记录中的单条记录（只包含查看者的公开信息）
+type ContactRevealItem struct {
+	UserID     int       `json:"user_id"`
+	Username   string    `json:"username"`
+	RevealedAt time.Time `json:"revealed_at"`
+}
+
+// GetContactRevealsResponse 获取联系方式查看记录响应
+type GetContactRevealsResponse struct {
+	Reveals    []ContactRevealItem `json:"reveals"`
+	TotalCount int64               `json:"total_count"` // 总数量
+	Page       int                 `json:"page"`        // 当前页码
+	PageSize   int                 `json:"page_size"`   // 每页数量
+	TotalPages int                 `json:"total_pages"` // 总页数
+}
+
+// GetContactReveals 获取商品联系方式的查看记录（分页，只有卖家可以查看）
+func GetContactReveals(req GetContactRevealsRequest) (*GetContactRevealsResponse, error) {
+	db := database.GetDB()
+
+	// 1. 设置默认值
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	// 2. 验证权限
+	var post models.Post
+	if err := db.Where("id = ? AND status != ?", req.PostID, constants.PostStatusDeleted).First(&post).Error; err != nil {
+		return nil, err
+	}
+	if post.Status == constants.PostStatusHidden && post.UserID != req.Actor.UserID && !req.Actor.Can(constants.PermissionModerate) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if !req.Actor.canManagePost(&post) {
+		return nil, fmt.Errorf("unauthorized: you can only view reveals of your own posts")
+	}
+
+	// 3. 构建查询
+	baseQuery := func() *gorm.DB {
+		return db.Table("contact_reveals").
+			Joins("JOIN users ON users.id = contact_reveals.user_id").
+			Where("contact_reveals.post_id = ?", post.ID)
+	}
+
+	// 4. 查询总数量
+	var totalCount int64
+	if err := baseQuery().Count(&totalCount).Error; err != nil {
+		return nil, err
+	}
+
+	// 5. 查询分页数据
+	reveals := []ContactRevealItem{}
+	if err := baseQuery().
+		Select("contact_reveals.user_id, users.username, contact_reveals.created_at AS revealed_at").
+		Order("contact_reveals.created_at DESC").
+		Limit(req.PageSize).
+		Offset((req.Page - 1) * req.PageSize).
+		Scan(&reveals).Error; err != nil {
+		return nil, err
+	}
+
+	return &GetContactRevealsResponse{
+		Reveals:    reveals,
+		TotalCount: totalCount,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: calcTotalPages(totalCount, req.PageSize),
+	}, nil
+}
+
+// maskContactInfo 隐藏商品的联系方式（卖家本人和已经查看过的用户可以看到完整联系方式）
+func maskContactInfo(posts []models.Post, viewerID int) error {
+	db := database.GetDB()
+
+	if len(posts) == 0 {
+		return nil
+	}
+	postIDs := make([]int, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	// 1. 查询当前用户查看过其中哪些商品的联系方式
+	var revealedIDs []int
+	if err := db.Model(&models.ContactReveal{}).
+		Where("user_id = ? AND post_id IN ?", viewerID, postIDs).
+		Pluck("post_id", &revealedIDs).Error; err != nil {
+		return err
+	}
+	revealed := make(map[int]bool, len(revealedIDs))
+	for _, id := range revealedIDs {
+		revealed[id] = true
+	}
+
+	// 2. 隐藏其余商品的联系方式
+	for i := range posts {
+		if posts[i].UserID == viewerID || revealed[posts[i].ID] {
+			continue
+		}
+		posts[i].ContactInfo = maskContact(posts[i].ContactInfo)
+		posts[i].ContactMasked = true
+	}
+	return nil
+}
+
+// maskContact 隐藏联系方式：邮箱只保留首字母和域名，电话只保留最后两位数字
+func maskContact(contact string) string {
+	contact = strings.TrimSpace(contact)
+	if contact == "" {
+		return ""
+	}
+
+	// 1. 邮箱
+	if at := strings.LastIndex(contact, "@"); at > 0 {
+		return string([]rune(contact)[:1]) + "***" + contact[at:]
+	}
+
+	// 2. 包含数字（电话等），只保留最后两位数字
+	digits := 0
+	for _, c := range contact {
+		if c >= '0' && c <= '9' {
+			digits++
+		}
+	}
+	if digits > 0 {
+		var b strings.Builder
+		seen := 0
+		for _, c := range contact {
+			if c >= '0' && c <= '9' {
+				seen++
+				if seen <= digits-2 {
+					c = '*'
+				}
+			}
+			b.WriteRune(c)
+		}
+		return b.String()
+	}
+
+	// 3. 其它内容只保留第一个字符
+	return string([]rune(contact)[:1]) + "***"
+}
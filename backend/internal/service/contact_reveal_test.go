@@ -0,0 +1,28 @@
+package service
+
+import "testing"
+
+func TestMaskContact(t *testing.T) {
+	tests := []struct {
+		name    string
+		contact string
+		want    string
+	}{
+		{name: "empty", contact: "", want: ""},
+		{name: "blank", contact: "   ", want: ""},
+		{name: "email", contact: "alice@example.com", want: "a***@example.com"},
+		{name: "multi-byte email", contact: "张三@example.com", want: "张***@example.com"},
+		{name: "phone", contact: "138-0013-8000", want: "***-****-**00"},
+		{name: "phone with multi-byte label", contact: "电话：13800138000", want: "电话：*********00"},
+		{name: "single digit", contact: "微信 7", want: "微信 7"},
+		{name: "multi-byte handle", contact: "微信号：小明", want: "微***"},
+		{name: "emoji handle", contact: "😀handle", want: "😀***"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maskContact(tt.contact); got != tt.want {
+				t.Fatalf("maskContact(%q) = %q, want %q", tt.contact, got, tt.want)
+			}
+		})
+	}
+}
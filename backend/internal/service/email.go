@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/email"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnqueueEmail 渲染邮件并写入发件箱
+// tx 应为触发邮件的业务事务，保证业务数据和邮件要么都写入，要么都不写入
+func EnqueueEmail(tx *gorm.DB, to string, template string, data interface{}) error {
+	if tx == nil {
+		tx = database.GetDB()
+	}
+
+	// 1. 渲染模板
+	msg, err := email.Render(template, to, data)
+	if err != nil {
+		return err
+	}
+
+	// 2. 写入发件箱，由后台任务投递
+	outbox := models.EmailOutbox{
+		ToAddress:     msg.To,
+		Template:      template,
+		Subject:       msg.Subject,
+		TextBody:      msg.TextBody,
+		HTMLBody:      msg.HTMLBody,
+		Status:        constants.EmailStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+	return tx.Create(&outbox).Error
+}
+
+// EmailNotificationSink 将站内通知同时发送到用户邮箱（遵循用户的邮件偏好）
+// 在服务启动时通过 RegisterNotificationSink 注册
+func EmailNotificationSink(tx *gorm.DB, notification *models.Notification) error {
+	// 1. 检查用户是否关闭了该类型的邮件
+	var pref models.NotificationPreference
+	err := tx.Where("user_id = ? AND type = ?", notification.UserID, notification.Type).First(&pref).Error
+	if err == nil && !pref.EmailEnabled {
+		return nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	// 2. 查询收件人
+	var user models.User
+	if err := tx.First(&user, notification.UserID).Error; err != nil {
+		return err
+	}
+
+	// 3. 生成邮件中的链接
+	link := ""
+	if notification.PostID != nil {
+		link = fmt.Sprintf("%s/item/%d", config.AppConfig.AppBaseURL, *notification.PostID)
+	}
+
+	return EnqueueEmail(tx, user.Email, email.TemplateNotification, map[string]interface{}{
+		"Username": user.Username,
+		"Title":    notification.Title,
+		"Body":     notification.Body,
+		"Link":     link,
+	})
+}
+
+// StartEmailWorker 启动发件箱投递任务，直到 ctx 结束
+func StartEmailWorker(ctx context.Context) {
+	sender := email.NewSMTPSender(
+		config.AppConfig.SMTPHost,
+		config.AppConfig.SMTPPort,
+		config.AppConfig.SMTPUsername,
+		config.AppConfig.SMTPPassword,
+		config.AppConfig.SMTPFrom,
+	)
+
+	ticker := time.NewTicker(constants.EmailWorkerIntervalSeconds * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := deliverPendingEmails(sender); err != nil {
+				log.Printf("⚠️  Email worker error: %v", err)
+			}
+		}
+	}
+}
+
+// deliverPendingEmails 投递一批到期的待发送邮件
+// 先在短事务中领取邮件（设置租约），提交后再发送，避免 SMTP 发送期间一直占用数据库连接和行锁
+func deliverPendingEmails(sender *email.SMTPSender) error {
+	// 1. 领取一批到期的邮件
+	pending, err := claimPendingEmails()
+	if err != nil {
+		return err
+	}
+
+	// 2. 逐封发送（不在事务中），每封邮件的结果单独记录
+	for i := range pending {
+		outbox := &pending[i]
+		sendErr := sender.Send(&email.Message{
+			To:       outbox.ToAddress,
+			Subject:  outbox.Subject,
+			TextBody: outbox.TextBody,
+			HTMLBody: outbox.HTMLBody,
+		})
+		if err := recordEmailResult(outbox, sendErr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// claimPendingEmails 领取一批到期的待发送邮件：增加尝试次数并设置租约
+// 使用 FOR UPDATE SKIP LOCKED，多个实例同时运行时不会领取同一封邮件
+func claimPendingEmails() ([]models.EmailOutbox, error) {
+	db := database.GetDB()
+
+	var pending []models.EmailOutbox
+	err := db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ? AND (locked_until IS NULL OR locked_until <= ?)",
+				constants.EmailStatusPending, now, now).
+			Order("id ASC").
+			Limit(constants.EmailWorkerBatchSize).
+			Find(&pending).Error; err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		ids := make([]int, len(pending))
+		for i := range pending {
+			ids[i] = pending[i].ID
+			pending[i].Attempts++
+		}
+		return tx.Model(&models.EmailOutbox{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"attempts":     gorm.Expr("attempts + 1"),
+			"locked_until": now.Add(constants.EmailLeaseSeconds * time.Second),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// recordEmailResult 记录一封邮件的发送结果并释放租约（outbox.Attempts 已包含本次尝试）
+func recordEmailResult(outbox *models.EmailOutbox, sendErr error) error {
+	db := database.GetDB()
+
+	updates := map[string]interface{}{"locked_until": nil}
+	if sendErr == nil {
+		updates["status"] = constants.EmailStatusSent
+		updates["sent_at"] = time.Now()
+		updates["last_error"] = ""
+	} else if outbox.Attempts >= constants.EmailMaxAttempts {
+		updates["status"] = constants.EmailStatusFailed
+		updates["last_error"] = sendErr.Error()
+		log.Printf("⚠️  Giving up on email %d to %s: %v", outbox.ID, outbox.ToAddress, sendErr)
+	} else {
+		// 指数退避：30s, 60s, 120s, ...
+		delay := time.Duration(constants.EmailRetryBaseSeconds<<(outbox.Attempts-1)) * time.Second
+		updates["next_attempt_at"] = time.Now().Add(delay)
+		updates["last_error"] = sendErr.Error()
+	}
+
+	return db.Model(outbox).Updates(updates).Error
+}
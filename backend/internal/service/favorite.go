@@ -0,0 +1,164 @@
+package service
+
+import (
+	"fmt"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddFavorite 收藏商品（重复收藏不会报错）
+func AddFavorite(userID int, postID int) error {
+	db := database.GetDB()
+
+	// 1. 确认商品存在且未被删除（已隐藏的商品只有卖家本人可以查看）
+	var post models.Post
+	if err := db.Where("id = ? AND status != ?", postID, constants.PostStatusDeleted).First(&post).Error; err != nil {
+		return err // 商品不存在
+	}
+	if post.Status == constants.PostStatusHidden && post.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+
+	// 2. 被卖家屏蔽的用户不能收藏
+	blocked, err := IsBlocked(post.UserID, userID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return fmt.Errorf("unauthorized: the seller has blocked you")
+	}
+
+	// 3. 保存收藏记录（已收藏时忽略）
+	favorite := models.Favorite{
+		UserID: userID,
+		PostID: postID,
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&favorite).Error
+}
+
+// RemoveFavorite 取消收藏（未收藏时不会报错）
+func RemoveFavorite(userID int, postID int) error {
+	db := database.GetDB()
+	return db.Where("user_id = ? AND post_id = ?", userID, postID).Delete(&models.Favorite{}).Error
+}
+
+// GetFavoritesRequest 获取收藏列表请求参数
+type GetFavoritesRequest struct {
+	UserID   int // 用户ID
+	Page     int // 页码，从1开始
+	PageSize int // 每页数量
+}
+
+// GetFavoritesResponse 获取收藏列表响应
+type GetFavoritesResponse struct {
+	Posts      []models.Post `json:"posts"`
+	TotalCount int64         `json:"total_count"` // 总数量
+	Page       int           `json:"page"`        // 当前页码
+	PageSize   int           `json:"page_size"`   // 每页数量
+	TotalPages int           `json:"total_pages"` // 总页数
+}
+
+// GetFavorites 获取我的收藏列表（分页）
+// 已售出或已删除的商品仍然保留在列表中，并显示其最终状态
+// 被管理员隐藏的商品（卖家本人的除外）和屏蔽了当前用户的卖家的商品不显示
+func GetFavorites(req GetFavoritesRequest) (*GetFavoritesResponse, error) {
+	db := database.GetDB()
+
+	// 1. 设置默认值
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 8
+	}
+
+	// 2. 计算偏移量
+	offset := (req.Page - 1) * req.PageSize
+
+	// 3. 查询总数量
+	baseQuery := func() *gorm.DB {
+		return excludeBlockedSellers(db.Model(&models.Post{}), req.UserID).
+			Joins("JOIN favorites ON favorites.post_id = posts.id AND favorites.user_id = ?", req.UserID).
+			Where("(posts.status != ? OR posts.user_id = ?)", constants.PostStatusHidden, req.UserID)
+	}
+	var totalCount int64
+	if err := baseQuery().Count(&totalCount).Error; err != nil {
+		return nil, err
+	}
+
+	// 4. 查询分页数据（按收藏时间倒序）
+	var posts []models.Post
+	if err := baseQuery().Preload("User").
+		Order("favorites.created_at DESC").
+		Limit(req.PageSize).
+		Offset(offset).
+		Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	// 5. 补充卖家评分、收藏信息等附加信息
+	if err := enrichPosts(posts, req.UserID); err != nil {
+		return nil, err
+	}
+
+	return &GetFavoritesResponse{
+		Posts:      posts,
+		TotalCount: totalCount,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: calcTotalPages(totalCount, req.PageSize),
+	}, nil
+}
+
+// attachFavoriteInfo 为商品附加收藏人数以及当前用户是否已收藏
+func attachFavoriteInfo(posts []models.Post, viewerID int) error {
+	db := database.GetDB()
+
+	if len(posts) == 0 {
+		return nil
+	}
+	postIDs := make([]int, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	// 1. 统计每个商品的收藏人数
+	var counts []struct {
+		PostID int
+		Count  int64
+	}
+	if err := db.Model(&models.Favorite{}).
+		Select("post_id, COUNT(*) AS count").
+		Where("post_id IN ?", postIDs).
+		Group("post_id").
+		Scan(&counts).Error; err != nil {
+		return err
+	}
+	countMap := make(map[int]int64, len(counts))
+	for _, c := range counts {
+		countMap[c.PostID] = c.Count
+	}
+
+	// 2. 查询当前用户收藏了其中哪些商品
+	var favoritedIDs []int
+	if err := db.Model(&models.Favorite{}).
+		Where("user_id = ? AND post_id IN ?", viewerID, postIDs).
+		Pluck("post_id", &favoritedIDs).Error; err != nil {
+		return err
+	}
+	favorited := make(map[int]bool, len(favoritedIDs))
+	for _, id := range favoritedIDs {
+		favorited[id] = true
+	}
+
+	for i := range posts {
+		posts[i].FavoriteCount = countMap[posts[i].ID]
+		posts[i].IsFavorited = favorited[posts[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FollowSeller 关注卖家（重复关注不会报错）
+// 卖家已有的在售商品会加入关注者的动态，并标记为已读
+func FollowSeller(followerID int, sellerID int) error {
+	db := database.GetDB()
+
+	// 1. 不能关注自己
+	if followerID == sellerID {
+		return fmt.Errorf("you cannot follow yourself")
+	}
+
+	// 2. 确认卖家存在且未被删除
+	var seller models.User
+	if err := db.Where("id = ? AND anonymized_at IS NULL", sellerID).First(&seller).Error; err != nil {
+		return err
+	}
+
+	// 3. 被卖家屏蔽的用户不能关注
+	blocked, err := IsBlocked(sellerID, followerID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return fmt.Errorf("unauthorized: the seller has blocked you")
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 4. 保存关注记录（已关注时忽略）
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.Follow{FollowerID: followerID, SellerID: sellerID})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		// 5. 将卖家最近的在售商品加入动态
+		var postIDs []int
+		if err := tx.Model(&models.Post{}).
+			Where("user_id = ? AND status = ?", sellerID, constants.PostStatusActive).
+			Order("created_at DESC").
+			Limit(constants.FeedBackfillPosts).
+			Pluck("id", &postIDs).Error; err != nil {
+			return err
+		}
+		if len(postIDs) == 0 {
+			return nil
+		}
+		now := time.Now()
+		items := make([]models.FeedItem, 0, len(postIDs))
+		for i := len(postIDs) - 1; i >= 0; i-- { // 旧商品先写入，保证动态按发布时间排序
+			items = append(items, models.FeedItem{UserID: followerID, PostID: postIDs[i], SellerID: sellerID, ReadAt: &now})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&items).Error
+	})
+}
+
+// UnfollowSeller 取消关注（未关注时不会报错），并从动态中移除该卖家的商品
+func UnfollowSeller(followerID int, sellerID int) error {
+	db := database.GetDB()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		return removeFollow(tx, followerID, sellerID)
+	})
+}
+
+// removeFollow 删除关注记录和对应的动态（需在数据库事务中调用）
+func removeFollow(tx *gorm.DB, followerID int, sellerID int) error {
+	if err := tx.Where("follower_id = ? AND seller_id = ?", followerID, sellerID).Delete(&models.Follow{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id = ? AND seller_id = ?", followerID, sellerID).Delete(&models.FeedItem{}).Error
+}
+
+// fanOutPostToFollowers 将新商品写入所有关注者的动态（未读）
+func fanOutPostToFollowers(tx *gorm.DB, post *models.Post) error {
+	return tx.Exec(`INSERT INTO feed_items (user_id, post_id, seller_id, created_at)
+		SELECT follower_id, ?, seller_id, ? FROM follows WHERE seller_id = ?
+		ON CONFLICT DO NOTHING`, post.ID, time.Now(), post.UserID).Error
+}
+
+// getFollowerCount 查询卖家的关注者数量
+func getFollowerCount(sellerID int) (int64, error) {
+	db := database.GetDB()
+
+	var count int64
+	if err := db.Model(&models.Follow{}).Where("seller_id = ?", sellerID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetFollowingRequest 获取关注列表请求参数
+type GetFollowingRequest struct {
+	UserID   int // 用户ID
+	Page     int // 页码，从1开始
+	PageSize int // 每页数量
+}
+
+// FollowingItem 关注列表中的单个卖家（只包含公开信息）
+type FollowingItem struct {
+	UserID     int       `json:"user_id"`
+	Username   string    `json:"username"`
+	AvatarURL  string    `json:"avatar_url"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+// GetFollowingResponse 获取关注列表响应
+type GetFollowingResponse struct {
+	Users      []FollowingItem `json:"users"`
+	TotalCount int64           `json:"total_count"` // 总数量
+	Page       int             `json:"page"`        // 当前页码
+	PageSize   int             `json:"page_size"`   // 每页数量
+	TotalPages int             `json:"total_pages"` // 总页数
+}
+
+// GetFollowing 获取我关注的卖家（分页）
+func GetFollowing(req GetFollowingRequest) (*GetFollowingResponse, error) {
+	db := database.GetDB()
+
+	// 1. 设置默认值
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 20
+	}
+
+	// 2. 构建查询
+	baseQuery := func() *gorm.DB {
+		return db.Table("follows").
+			Joins("JOIN users ON users.id = follows.seller_id").
+			Where("follows.follower_id = ?", req.UserID)
+	}
+
+	// 3. 查询总数量
+	var totalCount int64
+	if err := baseQuery().Count(&totalCount).Error; err != nil {
+		return nil, err
+	}
+
+	// 4. 查询分页数据
+	users := []FollowingItem{}
+	if err := baseQuery().
+		Select("follows.seller_id AS user_id, users.username, users.avatar_url, follows.created_at AS followed_at").
+		Order("follows.created_at DESC").
+		Limit(req.PageSize).
+		Offset((req.Page - 1) * req.PageSize).
+		Scan(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return &GetFollowingResponse{
+		Users:      users,
+		TotalCount: totalCount,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: calcTotalPages(totalCount, req.PageSize),
+	}, nil
+}
+
+// GetFeedRequest 获取关注动态请求参数
+type GetFeedRequest struct {
+	UserID int    // 用户ID
+	Cursor string // 上一页返回的 next_cursor，为空表示从最新开始
+	Limit  int    // 每页数量
+}
+
+// FeedEntry 动态中的单条记录
+type FeedEntry struct {
+	ID     int         `json:"id"`     // 动态ID
+	Unread bool        `json:"unread"` // 是否未读
+	Post   models.Post `json:"post"`
+}
+
+// GetFeedResponse 获取关注动态响应
+type GetFeedResponse struct {
+	Items       []FeedEntry `json:"items"`
+	NextCursor  *string     `json:"next_cursor"`  // 下一页游标，没有更多数据时为 null
+	UnreadCount int64       `json:"unread_count"` // 未读数量
+}
+
+// feedCursor 动态的分页位置：商品发布时间 + 动态ID（发布时间相同时按动态ID排序）
+type feedCursor struct {
+	PostCreatedAt time.Time
+	ID            int
+}
+
+// encode 编码为 "<发布时间的微秒时间戳>_<动态ID>"（PostgreSQL 时间精度为微秒）
+func (c feedCursor) encode() string {
+	return strconv.FormatInt(c.PostCreatedAt.UnixMicro(), 10) + "_" + strconv.Itoa(c.ID)
+}
+
+// parseFeedCursor 解析分页游标
+func parseFeedCursor(s string) (*feedCursor, error) {
+	micros, id, ok := strings.Cut(s, "_")
+	if !ok {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	us, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	itemID, err := strconv.Atoi(id)
+	if err != nil || itemID < 1 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &feedCursor{PostCreatedAt: time.UnixMicro(us), ID: itemID}, nil
+}
+
+// GetFeed 获取关注的卖家发布的在售商品（游标分页，按商品发布时间倒序）
+func GetFeed(req GetFeedRequest) (*GetFeedResponse, error) {
+	db := database.GetDB()
+
+	// 1. 设置默认值
+	if req.Limit < 1 {
+		req.Limit = constants.FeedDefaultLimit
+	}
+	if req.Limit > constants.FeedMaxLimit {
+		req.Limit = constants.FeedMaxLimit
+	}
+
+	// 2. 查询动态（多查一条用于判断是否还有下一页）
+	baseQuery := func() *gorm.DB {
+		return db.Model(&models.FeedItem{}).
+			Joins("JOIN posts ON posts.id = feed_items.post_id AND posts.status = ?", constants.PostStatusActive).
+			Where("feed_items.user_id = ?", req.UserID)
+	}
+	query := baseQuery()
+	if req.Cursor != "" {
+		cursor, err := parseFeedCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("(posts.created_at, feed_items.id) < (?, ?)", cursor.PostCreatedAt, cursor.ID)
+	}
+	var items []models.FeedItem
+	if err := query.Preload("Post").Preload("Post.User").
+		Order("posts.created_at DESC, feed_items.id DESC").
+		Limit(req.Limit + 1).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	var nextCursor *string
+	if len(items) > req.Limit {
+		items = items[:req.Limit]
+		last := items[len(items)-1]
+		cursor := feedCursor{PostCreatedAt: last.Post.CreatedAt, ID: last.ID}.encode()
+		nextCursor = &cursor
+	}
+
+	// 3. 补充卖家评分、收藏信息等附加信息
+	posts := make([]models.Post, len(items))
+	for i := range items {
+		posts[i] = items[i].Post
+	}
+	if err := enrichPosts(posts, req.UserID); err != nil {
+		return nil, err
+	}
+	entries := make([]FeedEntry, len(items))
+	for i := range items {
+		entries[i] = FeedEntry{ID: items[i].ID, Unread: items[i].ReadAt == nil, Post: posts[i]}
+	}
+
+	// 4. 查询未读数量
+	var unreadCount int64
+	if err := baseQuery().Where("feed_items.read_at IS NULL").Count(&unreadCount).Error; err != nil {
+		return nil, err
+	}
+
+	return &GetFeedResponse{
+		Items:       entries,
+		NextCursor:  nextCursor,
+		UnreadCount: unreadCount,
+	}, nil
+}
+
+// MarkFeedRead 将动态全部标记为已读
+func MarkFeedRead(userID int) error {
+	db := database.GetDB()
+	return db.Model(&models.FeedItem{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+}
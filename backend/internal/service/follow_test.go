@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFeedCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		cursor  string
+		want    feedCursor
+		wantErr bool
+	}{
+		{name: "valid", cursor: "1700000000123456_42", want: feedCursor{PostCreatedAt: time.UnixMicro(1700000000123456), ID: 42}},
+		{name: "before epoch", cursor: "-1_1", want: feedCursor{PostCreatedAt: time.UnixMicro(-1), ID: 1}},
+		{name: "empty", cursor: "", wantErr: true},
+		{name: "missing id", cursor: "1700000000123456", wantErr: true},
+		{name: "empty id", cursor: "1700000000123456_", wantErr: true},
+		{name: "zero id", cursor: "1700000000123456_0", wantErr: true},
+		{name: "negative id", cursor: "1700000000123456_-3", wantErr: true},
+		{name: "non-numeric time", cursor: "yesterday_42", wantErr: true},
+		{name: "rfc3339 time", cursor: "2023-11-14T22:13:20Z_42", wantErr: true},
+		{name: "extra part", cursor: "1700000000123456_42_7", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFeedCursor(tt.cursor)
+			if tt.wantErr {
+				if err == nil || err.Error() != "invalid cursor" {
+					t.Fatalf("expected invalid cursor, got %+v, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFeedCursor: %v", err)
+			}
+			if !got.PostCreatedAt.Equal(tt.want.PostCreatedAt) || got.ID != tt.want.ID {
+				t.Fatalf("cursor = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedCursorEncodeRoundTrip(t *testing.T) {
+	// PostgreSQL 保存到微秒，编码后不能丢失精度，否则翻页时会重复或跳过同一时间发布的商品
+	c := feedCursor{PostCreatedAt: time.Date(2024, 5, 1, 12, 30, 45, 123456000, time.UTC), ID: 9}
+	got, err := parseFeedCursor(c.encode())
+	if err != nil {
+		t.Fatalf("parseFeedCursor: %v", err)
+	}
+	if !got.PostCreatedAt.Equal(c.PostCreatedAt) || got.ID != c.ID {
+		t.Fatalf("cursor = %+v, want %+v", *got, c)
+	}
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// normalizeLoginEmail 统一邮箱格式，避免通过大小写绕过限制
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// CheckLoginAllowed 检查是否允许本次登录尝试
+// 返回需要等待的时间；被锁定或处于退避期时返回 "too many login attempts" 错误
+// 不论邮箱是否已注册都执行相同的检查，避免泄露账号是否存在
+func CheckLoginAllowed(email string, ip string) (time.Duration, error) {
+	db := database.GetDB()
+	email = normalizeLoginEmail(email)
+	now := time.Now()
+
+	// 1. 检查邮箱或IP是否处于锁定状态
+	var lockout models.LoginLockout
+	err := db.Where("((scope = ? AND target = ?) OR (scope = ? AND target = ?)) AND locked_until > ?",
+		constants.LoginLockoutScopeEmail, email, constants.LoginLockoutScopeIP, ip, now).
+		Order("locked_until DESC").
+		First(&lockout).Error
+	if err == nil {
+		return lockout.LockedUntil.Sub(now), fmt.Errorf("too many login attempts")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	// 2. 统计该邮箱最近连续失败的次数
+	failures, lastFailure, err := recentLoginFailures(db, "email", email)
+	if err != nil {
+		return 0, err
+	}
+
+	// 3. 超过阈值后指数退避：1s, 2s, 4s ... 最长 LoginBackoffMaxSeconds
+	if failures >= constants.LoginBackoffThreshold {
+		shift := failures - constants.LoginBackoffThreshold
+		delay := time.Duration(constants.LoginBackoffMaxSeconds) * time.Second
+		if shift < 6 {
+			delay = min(time.Second<<shift, delay)
+		}
+		if wait := lastFailure.Add(delay).Sub(now); wait > 0 {
+			return wait, fmt.Errorf("too many login attempts")
+		}
+	}
+
+	return 0, nil
+}
+
+// RecordLoginAttempt 记录一次登录尝试，失败次数过多时锁定邮箱或IP
+// actor 只用于审计日志（IP 即本次尝试的来源IP，UserID 通常为 0）
+func RecordLoginAttempt(actor Actor, email string, success bool) error {
+	db := database.GetDB()
+	email = normalizeLoginEmail(email)
+	ip := actor.IP
+
+	// 1. 保存登录记录
+	attempt := models.LoginAttempt{Email: email, IP: ip, Success: success}
+	if err := db.Create(&attempt).Error; err != nil {
+		return err
+	}
+	if success {
+		return nil
+	}
+
+	// 2. 检查是否需要锁定
+	checks := []struct {
+		scope      string
+		column     string
+		key        string
+		threshold  int64
+		targetType string
+		targetID   string // 审计日志中的目标，邮箱只保存哈希
+	}{
+		{constants.LoginLockoutScopeEmail, "email", email, constants.LoginEmailLockoutFailures, constants.AuditTargetEmail, AuditEmailHash(email)},
+		{constants.LoginLockoutScopeIP, "ip", ip, constants.LoginIPLockoutFailures, constants.AuditTargetIP, ip},
+	}
+	for _, check := range checks {
+		failures, _, err := recentLoginFailures(db, check.column, check.key)
+		if err != nil {
+			return err
+		}
+		if failures < check.threshold {
+			continue
+		}
+
+		// 3. 记录锁定事件，并写入审计日志（管理员可在审计日志中查看）
+		lockout := models.LoginLockout{
+			Scope:       check.scope,
+			Target:      check.key,
+			IP:          ip,
+			Failures:    failures,
+			LockedUntil: time.Now().Add(constants.LoginLockoutMinutes * time.Minute),
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&lockout).Error; err != nil {
+				return err
+			}
+			return recordAudit(tx, actor, constants.AuditActionLoginLockout, check.targetType, check.targetID, nil, models.AuditState{
+				"failures":     failures,
+				"locked_until": lockout.LockedUntil,
+			})
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("⚠️  Login locked for %s %s after %d failures", check.scope, check.key, failures)
+	}
+	return nil
+}
+
+// recentLoginFailures 统计时间窗口内最近一次成功登录之后的失败次数，以及最后一次失败的时间
+// column 为 "email" 或 "ip"
+func recentLoginFailures(db *gorm.DB, column string, key string) (int64, time.Time, error) {
+	since := time.Now().Add(-constants.LoginFailureWindowMinutes * time.Minute)
+
+	// 1. 锁定结束后重新计数
+	var lastLockout models.LoginLockout
+	scope := constants.LoginLockoutScopeEmail
+	if column == "ip" {
+		scope = constants.LoginLockoutScopeIP
+	}
+	if err := db.Where("scope = ? AND target = ?", scope, key).Order("locked_until DESC").
+		Limit(1).Find(&lastLockout).Error; err != nil {
+		return 0, time.Time{}, err
+	}
+	if lastLockout.ID != 0 && lastLockout.LockedUntil.After(since) {
+		since = lastLockout.LockedUntil
+	}
+
+	// 2. 按邮箱统计时，成功登录后重新计数
+	if column == "email" {
+		var lastSuccess models.LoginAttempt
+		if err := db.Where("email = ? AND success = ? AND created_at > ?", key, true, since).
+			Order("created_at DESC").Limit(1).Find(&lastSuccess).Error; err != nil {
+			return 0, time.Time{}, err
+		}
+		if lastSuccess.ID != 0 {
+			since = lastSuccess.CreatedAt
+		}
+	}
+
+	// 3. 统计失败次数和最后一次失败时间
+	var result struct {
+		Failures    int64
+		LastFailure *time.Time
+	}
+	if err := db.Model(&models.LoginAttempt{}).
+		Select("COUNT(*) AS failures, MAX(created_at) AS last_failure").
+		Where(fmt.Sprintf("%s = ? AND success = ? AND created_at > ?", column), key, false, since).
+		Scan(&result).Error; err != nil {
+		return 0, time.Time{}, err
+	}
+	if result.LastFailure == nil {
+		return 0, time.Time{}, nil
+	}
+	return result.Failures, *result.LastFailure, nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IsValidReportReason 判断举报原因是否合法
+func IsValidReportReason(reason string) bool {
+	switch reason {
+	case constants.ReportReasonScam, constants.ReportReasonProhibited, constants.ReportReasonCounterfeit,
+		constants.ReportReasonSpam, constants.ReportReasonOffensive, constants.ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+// ReportPostRequest 举报商品请求
+type ReportPostRequest struct {
+	PostID     int    // 商品ID
+	ReporterID int    // 举报者ID
+	Reason     string // 举报原因
+	Details    string // 举报说明（可选）
+}
+
+// ReportPost 举报商品（同一用户对同一商品只能举报一次）
+func ReportPost(req ReportPostRequest) (*models.Report, error) {
+	db := database.GetDB()
+
+	// 1. 验证举报原因
+	if !IsValidReportReason(req.Reason) {
+		return nil, fmt.Errorf("invalid reason")
+	}
+
+	// 2. 确认商品存在且未被删除，不能举报自己的商品
+	var post models.Post
+	if err := db.Where("id = ? AND status != ?", req.PostID, constants.PostStatusDeleted).First(&post).Error; err != nil {
+		return nil, err
+	}
+	if post.UserID == req.ReporterID {
+		return nil, fmt.Errorf("you cannot report your own post")
+	}
+
+	// 3. 保存举报（已举报过时返回错误）
+	report := models.Report{
+		PostID:     post.ID,
+		ReporterID: req.ReporterID,
+		Reason:     req.Reason,
+		Details:    req.Details,
+		Status:     constants.ReportStatusPending,
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&report)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("post already reported")
+	}
+
+	return &report, nil
+}
+
+// GetModerationQueueRequest 获取审核队列请求参数
+type GetModerationQueueRequest struct {
+	Actor    Actor  // 操作者（需要审核权限）
+	Status   string // 举报状态，默认 pending
+	Page     int    // 页码，从1开始
+	PageSize int    // 每页数量
+}
+
+// ModerationQueueItem 审核队列中的一个被举报商品（同一商品的举报合并显示）
+type ModerationQueueItem struct {
+	PostID          int       `json:"post_id"`
+	PostTitle       string    `json:"post_title"`
+	PostStatus      string    `json:"post_status"`
+	SellerID        int       `json:"seller_id"`
+	SellerUsername  string    `json:"seller_username"`
+	ReportCount     int64     `json:"report_count"`
+	FirstReportedAt time.Time `json:"first_reported_at"`
+	LastReportedAt  time.Time `json:"last_reported_at"`
+}
+
+// GetModerationQueueResponse 获取审核队列响应
+type GetModerationQueueResponse struct {
+	Items      []ModerationQueueItem `json:"items"`
+	TotalCount int64                 `json:"total_count"` // 总数量
+	Page       int                   `json:"page"`        // 当前页码
+	PageSize   int                   `json:"page_size"`   // 每页数量
+	TotalPages int                   `json:"total_pages"` // 总页数
+}
+
+// GetModerationQueue 获取审核队列（按商品分组，举报最多的排在前面）
+func GetModerationQueue(req GetModerationQueueRequest) (*GetModerationQueueResponse, error) {
+	db := database.GetDB()
+
+	// 1. 验证权限
+	if !req.Actor.Can(constants.PermissionModerate) {
+		return nil, fmt.Errorf("unauthorized: you cannot moderate reports")
+	}
+
+	// 2. 设置默认值
+	if req.Status == "" {
+		req.Status = constants.ReportStatusPending
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 20
+	}
+
+	// 3. 查询总数量（被举报的商品数）
+	var totalCount int64
+	if err := db.Model(&models.Report{}).
+		Where("status = ?", req.Status).
+		Distinct("post_id").
+		Count(&totalCount).Error; err != nil {
+		return nil, err
+	}
+
+	// 4. 查询分页数据
+	items := []ModerationQueueItem{}
+	if err := db.Table("reports").
+		Joins("JOIN posts ON posts.id = reports.post_id").
+		Joins("JOIN users ON users.id = posts.user_id").
+		Where("reports.status = ?", req.Status).
+		Select("reports.post_id, posts.title AS post_title, posts.status AS post_status, " +
+			"posts.user_id AS seller_id, users.username AS seller_username, COUNT(*) AS report_count, " +
+			"MIN(reports.created_at) AS first_reported_at, MAX(reports.created_at) AS last_reported_at").
+		Group("reports.post_id, posts.title, posts.status, posts.user_id, users.username").
+		Order("report_count DESC, first_reported_at ASC").
+		Limit(req.PageSize).
+		Offset((req.Page - 1) * req.PageSize).
+		Scan(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return &GetModerationQueueResponse{
+		Items:      items,
+		TotalCount: totalCount,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: calcTotalPages(totalCount, req.PageSize),
+	}, nil
+}
+
+// ReportedPostDetail 被举报商品的审核详情
+type ReportedPostDetail struct {
+	Post      models.Post                 `json:"post"`
+	Reports   []models.Report             `json:"reports"`   // 所有举报（包括已处理的）
+	Decisions []models.ModerationDecision `json:"decisions"` // 历史审核决定
+}
+
+// GetReportedPost 获取被举报商品的详情、举报和历史审核决定
+func GetReportedPost(actor Actor, postID int) (*ReportedPostDetail, error) {
+	db := database.GetDB()
+
+	// 1. 验证权限
+	if !actor.Can(constants.PermissionModerate) {
+		return nil, fmt.Errorf("unauthorized: you cannot moderate reports")
+	}
+
+	// 2. 查询商品（包括已隐藏和已删除的商品）
+	var detail ReportedPostDetail
+	if err := db.Preload("User").First(&detail.Post, postID).Error; err != nil {
+		return nil, err
+	}
+
+	// 3. 查询举报和审核决定
+	if err := db.Where("post_id = ?", postID).Order("created_at DESC").Find(&detail.Reports).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("post_id = ?", postID).Order("created_at DESC").Find(&detail.Decisions).Error; err != nil {
+		return nil, err
+	}
+
+	return &detail, nil
+}
+
+// DecideReportsRequest 处理举报请求
+type DecideReportsRequest struct {
+	Actor  Actor  // 操作者（需要审核权限）
+	PostID int    // 被举报的商品ID
+	Action string // 处理方式：dismiss, hide_post, ban_user
+	Note   string // 处理说明（封禁时作为封禁原因）
+}
+
+// DecideReports 处理商品的所有待处理举报，并记录审核决定
+func DecideReports(req DecideReportsRequest) (*models.ModerationDecision, error) {
+	db := database.GetDB()
+
+	// 1. 验证权限和处理方式
+	if !req.Actor.Can(constants.PermissionModerate) {
+		return nil, fmt.Errorf("unauthorized: you cannot moderate reports")
+	}
+	reportStatus := constants.ReportStatusActioned
+	switch req.Action {
+	case constants.ModerationActionDismiss:
+		reportStatus = constants.ReportStatusDismissed
+	case constants.ModerationActionHidePost, constants.ModerationActionBanUser:
+	default:
+		return nil, fmt.Errorf("invalid action")
+	}
+
+	var decision models.ModerationDecision
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 2. 查询商品并加锁
+		var post models.Post
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&post, req.PostID).Error; err != nil {
+			return err
+		}
+		oldStatus := post.Status
+
+		// 3. 执行处理
+		switch req.Action {
+		case constants.ModerationActionHidePost:
+			if err := hidePost(tx, &post, constants.HiddenReasonModeration); err != nil {
+				return err
+			}
+		case constants.ModerationActionBanUser:
+			// 先以审核原因隐藏被举报的商品，解除封禁时不会被重新上架
+			if err := hidePost(tx, &post, constants.HiddenReasonModeration); err != nil {
+				return err
+			}
+			if err := suspendUser(tx, req.Actor, post.UserID, req.Note, nil); err != nil {
+				return err
+			}
+		}
+
+		// 4. 记录审核决定，并更新待处理的举报
+		var pending []models.Report
+		if err := tx.Where("post_id = ? AND status = ?", post.ID, constants.ReportStatusPending).
+			Find(&pending).Error; err != nil {
+			return err
+		}
+		decision = models.ModerationDecision{
+			PostID:      post.ID,
+			ModeratorID: req.Actor.UserID,
+			Action:      req.Action,
+			Note:        req.Note,
+			ReportCount: len(pending),
+		}
+		if err := tx.Create(&decision).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Report{}).
+			Where("post_id = ? AND status = ?", post.ID, constants.ReportStatusPending).
+			Updates(map[string]interface{}{"status": reportStatus, "decision_id": decision.ID}).Error; err != nil {
+			return err
+		}
+
+		// 5. 记录审计日志（封禁时商品状态由 suspendUser 批量修改，需要重新查询）
+		if err := tx.Select("status").First(&post, post.ID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, req.Actor, constants.AuditActionReportDecision, constants.AuditTargetPost, post.ID,
+			models.AuditState{"status": oldStatus},
+			models.AuditState{"status": post.Status, "action": req.Action, "note": req.Note, "decision_id": decision.ID, "report_count": len(pending)})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &decision, nil
+}
+
+// hidePost 隐藏商品（需在数据库事务中调用，已删除的商品不处理）
+func hidePost(tx *gorm.DB, post *models.Post, reason string) error {
+	if post.Status == constants.PostStatusDeleted {
+		return nil
+	}
+	return tx.Model(post).Updates(map[string]interface{}{
+		"status":        constants.PostStatusHidden,
+		"hidden_reason": reason,
+	}).Error
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// notificationTypes 所有支持的通知类型
+// new_message 和 offer_received 为预留类型，对应功能上线后由其发出，用户可以提前设置偏好
+var notificationTypes = []string{
+	constants.NotificationTypeNewMessage,
+	constants.NotificationTypeOfferReceived,
+	constants.NotificationTypeItemSold,
+	constants.NotificationTypeReviewLeft,
+	constants.NotificationTypePriceDrop,
+}
+
+// isValidNotificationType 判断通知类型是否合法
+func isValidNotificationType(notificationType string) bool {
+	for _, t := range notificationTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationEvent 通知事件，由各业务功能发出
+type NotificationEvent struct {
+	UserID int    // 接收者
+	Type   string // 通知类型
+	Title  string // 标题
+	Body   string // 内容
+	PostID *int   // 相关商品（可选）
+}
+
+// NotificationSink 通知投递钩子，站内通知写入后在同一事务中调用
+// 用于扩展其他投递渠道（例如邮件）
+type NotificationSink func(tx *gorm.DB, notification *models.Notification) error
+
+var notificationSinks []NotificationSink
+
+// RegisterNotificationSink 注册通知投递钩子（应在服务启动时调用）
+func RegisterNotificationSink(sink NotificationSink) {
+	notificationSinks = append(notificationSinks, sink)
+}
+
+// Notify 分发通知：过滤掉用户已关闭的通知类型，写入站内通知，再调用投递钩子
+// tx 为触发通知的业务事务，传入 nil 时使用默认连接
+func Notify(tx *gorm.DB, events ...NotificationEvent) error {
+	if tx == nil {
+		tx = database.GetDB()
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	// 1. 查询接收者关闭的通知类型
+	userIDs := make([]int, 0, len(events))
+	for _, event := range events {
+		userIDs = append(userIDs, event.UserID)
+	}
+	var disabled []models.NotificationPreference
+	if err := tx.Where("user_id IN ? AND enabled = ?", userIDs, false).Find(&disabled).Error; err != nil {
+		return err
+	}
+	muted := make(map[string]bool, len(disabled))
+	for _, pref := range disabled {
+		muted[fmt.Sprintf("%d:%s", pref.UserID, pref.Type)] = true
+	}
+
+	// 2. 生成站内通知
+	notifications := make([]models.Notification, 0, len(events))
+	for _, event := range events {
+		if muted[fmt.Sprintf("%d:%s", event.UserID, event.Type)] {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserID: event.UserID,
+			Type:   event.Type,
+			Title:  event.Title,
+			Body:   event.Body,
+			PostID: event.PostID,
+		})
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+	if err := tx.Create(&notifications).Error; err != nil {
+		return err
+	}
+
+	// 3. 调用投递钩子
+	for i := range notifications {
+		for _, sink := range notificationSinks {
+			if err := sink(tx, &notifications[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// notifyFavoriters 通知收藏了该商品的用户（不包括卖家本人，需在数据库事务中调用）
+func notifyFavoriters(tx *gorm.DB, post *models.Post, notificationType, title, body string) error {
+	var userIDs []int
+	if err := tx.Model(&models.Favorite{}).
+		Where("post_id = ? AND user_id != ?", post.ID, post.UserID).
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return err
+	}
+
+	events := make([]NotificationEvent, 0, len(userIDs))
+	for _, userID := range userIDs {
+		postID := post.ID
+		events = append(events, NotificationEvent{
+			UserID: userID,
+			Type:   notificationType,
+			Title:  title,
+			Body:   body,
+			PostID: &postID,
+		})
+	}
+	return Notify(tx, events...)
+}
+
+// notifyPriceDrop 通知收藏了该商品的用户商品已降价
+func notifyPriceDrop(tx *gorm.DB, post *models.Post, oldPrice float64) error {
+	return notifyFavoriters(tx, post, constants.NotificationTypePriceDrop,
+		"Price drop on an item you saved",
+		fmt.Sprintf("%s dropped from $%.2f to $%.2f", post.Title, oldPrice, post.Price))
+}
+
+// notifyItemSold 通知收藏者商品已售出
+func notifyItemSold(tx *gorm.DB, post *models.Post) error {
+	return notifyFavoriters(tx, post, constants.NotificationTypeItemSold,
+		"An item you saved has been sold",
+		fmt.Sprintf("%s is no longer available", post.Title))
+}
+
+// GetNotificationsRequest 获取通知列表请求参数
+type GetNotificationsRequest struct {
+	UserID     int  // 用户ID
+	UnreadOnly bool // 是否只返回未读通知
+	Page       int  // 页码，从1开始
+	PageSize   int  // 每页数量
+}
+
+// GetNotificationsResponse 获取通知列表响应
+type GetNotificationsResponse struct {
+	Notifications []models.Notification `json:"notifications"`
+	TotalCount    int64                 `json:"total_count"` // 总数量
+	Page          int                   `json:"page"`        // 当前页码
+	PageSize      int                   `json:"page_size"`   // 每页数量
+	TotalPages    int                   `json:"total_pages"` // 总页数
+}
+
+// GetNotifications 获取我的通知列表（分页，最新的在前）
+func GetNotifications(req GetNotificationsRequest) (*GetNotificationsResponse, error) {
+	db := database.GetDB()
+
+	// 1. 设置默认值
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 20
+	}
+
+	// 2. 构建查询条件
+	baseQuery := func() *gorm.DB {
+		query := db.Model(&models.Notification{}).Where("user_id = ?", req.UserID)
+		if req.UnreadOnly {
+			query = query.Where("read_at IS NULL")
+		}
+		return query
+	}
+
+	// 3. 查询总数量
+	var totalCount int64
+	if err := baseQuery().Count(&totalCount).Error; err != nil {
+		return nil, err
+	}
+
+	// 4. 查询分页数据
+	notifications := []models.Notification{}
+	if err := baseQuery().Order("created_at DESC, id DESC").
+		Limit(req.PageSize).
+		Offset((req.Page - 1) * req.PageSize).
+		Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+
+	return &GetNotificationsResponse{
+		Notifications: notifications,
+		TotalCount:    totalCount,
+		Page:          req.Page,
+		PageSize:      req.PageSize,
+		TotalPages:    calcTotalPages(totalCount, req.PageSize),
+	}, nil
+}
+
+// GetUnreadNotificationCount 获取未读通知数量
+func GetUnreadNotificationCount(userID int) (int64, error) {
+	db := database.GetDB()
+
+	var count int64
+	if err := db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// MarkNotificationRead 将一条通知标记为已读
+func MarkNotificationRead(userID int, notificationID int) error {
+	db := database.GetDB()
+
+	// 1. 查询通知是否存在且属于当前用户
+	var notification models.Notification
+	if err := db.Where("id = ? AND user_id = ?", notificationID, userID).First(&notification).Error; err != nil {
+		return err // 通知不存在
+	}
+
+	// 2. 已读的通知不需要再更新
+	if notification.ReadAt != nil {
+		return nil
+	}
+	return db.Model(&notification).Update("read_at", time.Now()).Error
+}
+
+// MarkAllNotificationsRead 将所有未读通知标记为已读
+func MarkAllNotificationsRead(userID int) error {
+	db := database.GetDB()
+	return db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+}
+
+// NotificationSetting 某种通知类型的接收设置
+type NotificationSetting struct {
+	Enabled      bool `json:"enabled"`       // 是否接收
+	EmailEnabled bool `json:"email_enabled"` // 是否同时发送邮件
+}
+
+// NotificationSettingUpdate 通知设置的修改（未传入的字段保持不变）
+type NotificationSettingUpdate struct {
+	Enabled      *bool `json:"enabled"`
+	EmailEnabled *bool `json:"email_enabled"`
+}
+
+// GetNotificationPreferences 获取用户对每种通知类型的接收偏好
+func GetNotificationPreferences(userID int) (map[string]NotificationSetting, error) {
+	db := database.GetDB()
+
+	// 1. 默认接收所有类型，并发送邮件
+	preferences := make(map[string]NotificationSetting, len(notificationTypes))
+	for _, t := range notificationTypes {
+		preferences[t] = NotificationSetting{Enabled: true, EmailEnabled: true}
+	}
+
+	// 2. 用用户保存的设置覆盖默认值
+	var saved []models.NotificationPreference
+	if err := db.Where("user_id = ?", userID).Find(&saved).Error; err != nil {
+		return nil, err
+	}
+	for _, pref := range saved {
+		if _, ok := preferences[pref.Type]; ok {
+			preferences[pref.Type] = NotificationSetting{Enabled: pref.Enabled, EmailEnabled: pref.EmailEnabled}
+		}
+	}
+	return preferences, nil
+}
+
+// UpdateNotificationPreferences 更新用户的通知偏好（只更新传入的类型）
+func UpdateNotificationPreferences(userID int, updates map[string]NotificationSettingUpdate) (map[string]NotificationSetting, error) {
+	db := database.GetDB()
+
+	// 1. 验证通知类型
+	for t := range updates {
+		if !isValidNotificationType(t) {
+			return nil, fmt.Errorf("invalid notification type: %s", t)
+		}
+	}
+
+	// 2. 在当前设置的基础上合并修改
+	current, err := GetNotificationPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 保存设置（已存在则更新）
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for t, update := range updates {
+			setting := current[t]
+			if update.Enabled != nil {
+				setting.Enabled = *update.Enabled
+			}
+			if update.EmailEnabled != nil {
+				setting.EmailEnabled = *update.EmailEnabled
+			}
+
+			pref := models.NotificationPreference{
+				UserID:       userID,
+				Type:         t,
+				Enabled:      setting.Enabled,
+				EmailEnabled: setting.EmailEnabled,
+			}
+			// 显式指定字段，避免 false 被当作零值而使用数据库默认值
+			if err := tx.Select("user_id", "type", "enabled", "email_enabled", "updated_at").Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+				DoUpdates: clause.AssignmentColumns([]string{"enabled", "email_enabled", "updated_at"}),
+			}).Create(&pref).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return GetNotificationPreferences(userID)
+}
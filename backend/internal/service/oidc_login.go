@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/internal/oidc"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	oidcProviderMu sync.Mutex
+	oidcProvider   *oidc.Provider
+)
+
+// getOIDCProvider 获取 OIDC 身份提供方（第一次使用时读取发现文档，失败后下次重试）
+func getOIDCProvider(ctx context.Context) (*oidc.Provider, error) {
+	cfg := config.AppConfig
+	if cfg.OIDCIssuer == "" || cfg.OIDCClientID == "" {
+		return nil, fmt.Errorf("oidc login not configured")
+	}
+
+	oidcProviderMu.Lock()
+	defer oidcProviderMu.Unlock()
+
+	if oidcProvider == nil {
+		provider, err := oidc.NewProvider(ctx, cfg.OIDCIssuer, cfg.OIDCClientID, cfg.OIDCClientSecret, cfg.OIDCRedirectURL)
+		if err != nil {
+			return nil, err
+		}
+		oidcProvider = provider
+	}
+	return oidcProvider, nil
+}
+
+// OIDCLoginStart 发起第三方登录的结果
+type OIDCLoginStart struct {
+	AuthorizationURL string `json:"authorization_url"` // 前端跳转到该地址
+	State            string `json:"-"`                 // 由 handler 写入浏览器 Cookie，回调时核对
+}
+
+// StartOIDCLogin 发起第三方登录：生成 state、nonce 和 PKCE verifier，返回授权地址
+func StartOIDCLogin(ctx context.Context) (*OIDCLoginStart, error) {
+	provider, err := getOIDCProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 生成随机值
+	state, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 保存登录状态（顺便清理已过期的记录）
+	db := database.GetDB()
+	if err := db.Where("expires_at < ?", time.Now()).Delete(&models.OIDCLoginState{}).Error; err != nil {
+		return nil, err
+	}
+	loginState := models.OIDCLoginState{
+		StateHash:    utils.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(constants.OIDCStateExpiryMinutes * time.Minute),
+	}
+	if err := db.Create(&loginState).Error; err != nil {
+		return nil, err
+	}
+
+	return &OIDCLoginStart{AuthorizationURL: provider.AuthCodeURL(state, nonce, verifier), State: state}, nil
+}
+
+// CompleteOIDCLogin 处理第三方登录回调：校验 state，换取并验证 ID Token，找到或创建对应的用户
+// 绑定顺序：已绑定的外部身份 -> 邮箱已验证的同邮箱账号 -> 新建账号
+func CompleteOIDCLogin(ctx context.Context, code string, state string) (*models.User, error) {
+	provider, err := getOIDCProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 校验并使用 state（一次性）
+	loginState, err := consumeOIDCLoginState(state)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 换取并验证 ID Token
+	claims, err := provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("oidc authentication failed: %w", err)
+	}
+
+	// 3. 找到或创建用户
+	db := database.GetDB()
+	var user models.User
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// 3.1 已绑定过的外部身份
+		var identity models.UserIdentity
+		err := tx.Where("issuer = ? AND subject = ?", provider.Issuer(), claims.Subject).First(&identity).Error
+		if err == nil {
+			return tx.First(&user, identity.UserID).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// 3.2 只有提供方确认过的邮箱才能用于绑定或注册
+		email := strings.TrimSpace(claims.Email)
+		if email == "" || !bool(claims.EmailVerified) {
+			return fmt.Errorf("verified email required")
+		}
+
+		// 3.3 绑定到同邮箱的已有账号
+		// 本地账号的邮箱必须已验证，否则可能是他人抢先用该邮箱注册的账号
+		err = tx.Where("LOWER(email) = LOWER(?)", email).First(&user).Error
+		if err == nil {
+			if !user.EmailVerified {
+				return fmt.Errorf("account email not verified")
+			}
+		} else if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 3.4 新建账号
+			if err := createOIDCUser(tx, &user, email, claims); err != nil {
+				return err
+			}
+		} else {
+			return err
+		}
+
+		identity = models.UserIdentity{
+			UserID:  user.ID,
+			Issuer:  provider.Issuer(),
+			Subject: claims.Subject,
+			Email:   email,
+		}
+		return tx.Create(&identity).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// consumeOIDCLoginState 校验并删除登录状态
+func consumeOIDCLoginState(state string) (*models.OIDCLoginState, error) {
+	db := database.GetDB()
+
+	var loginState models.OIDCLoginState
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("state_hash = ?", utils.HashToken(state)).
+			First(&loginState).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("invalid or expired state")
+			}
+			return err
+		}
+		return tx.Delete(&loginState).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(loginState.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired state")
+	}
+	return &loginState, nil
+}
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// createOIDCUser 为第三方登录的新用户创建账号
+// 密码设置为随机值（用户之后可以通过忘记密码设置密码），邮箱已由提供方验证
+func createOIDCUser(tx *gorm.DB, user *models.User, email string, claims *oidc.IDTokenClaims) error {
+	// 1. 生成一个不可用的随机密码
+	randomPassword, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+	passwordHash, err := utils.HashPassword(randomPassword)
+	if err != nil {
+		return err
+	}
+
+	// 2. 根据提供方的用户名或邮箱前缀生成不重复的用户名
+	username, err := uniqueUsername(tx, claims.PreferredUsername, email)
+	if err != nil {
+		return err
+	}
+
+	*user = models.User{
+		Username:      username,
+		Email:         email,
+		PasswordHash:  passwordHash,
+		EmailVerified: true,
+	}
+	return tx.Create(user).Error
+}
+
+// uniqueUsername 生成不与现有用户重复的用户名
+func uniqueUsername(tx *gorm.DB, preferred string, email string) (string, error) {
+	base := preferred
+	if base == "" || strings.Contains(base, "@") {
+		base = strings.SplitN(email, "@", 2)[0]
+	}
+	base = usernameInvalidChars.ReplaceAllString(base, "")
+	if base == "" {
+		base = "user"
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	candidate := base
+	for i := 0; i < 10; i++ {
+		var count int64
+		if err := tx.Model(&models.User{}).Where("username = ?", candidate).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+
+		suffix, err := utils.GenerateRandomToken(3)
+		if err != nil {
+			return "", err
+		}
+		candidate = base + "_" + usernameInvalidChars.ReplaceAllString(suffix, "")
+	}
+	return "", fmt.Errorf("failed to generate a unique username")
+}
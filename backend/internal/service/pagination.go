@@ -0,0 +1,10 @@
+package service
+
+// calcTotalPages 根据总数量和每页数量计算总页数
+func calcTotalPages(totalCount int64, pageSize int) int {
+	totalPages := int(totalCount) / pageSize
+	if int(totalCount)%pageSize != 0 {
+		totalPages++
+	}
+	return totalPages
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/email"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// RequestPasswordReset 发送密码重置邮件
+// 邮箱不存在时同样返回成功，避免通过该接口探测已注册的邮箱
+func RequestPasswordReset(emailAddress string) error {
+	db := database.GetDB()
+
+	// 1. 查找用户（不存在时直接返回）
+	var user models.User
+	if err := db.Where("email = ?", emailAddress).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 2. 限制发送频率（频繁请求时静默忽略）
+		if err := checkTokenResendCooldown(tx, user.ID, constants.TokenPurposePasswordReset); err != nil {
+			return nil
+		}
+
+		// 3. 生成重置令牌并写入发件箱
+		ttl := constants.PasswordResetExpiryMinutes * time.Minute
+		token, err := createUserToken(tx, user.ID, constants.TokenPurposePasswordReset, ttl)
+		if err != nil {
+			return err
+		}
+		return EnqueueEmail(tx, user.Email, email.TemplatePasswordReset, map[string]interface{}{
+			"Username":  user.Username,
+			"Link":      config.AppConfig.AppBaseURL + "/reset-password?token=" + url.QueryEscape(token),
+			"ExpiresIn": fmt.Sprintf("%d minutes", constants.PasswordResetExpiryMinutes),
+		})
+	})
+}
+
+// ResetPassword 使用重置令牌设置新密码，并注销该用户的所有会话和个人访问令牌，返回用户ID
+func ResetPassword(token string, newPassword string) (int, error) {
+	db := database.GetDB()
+
+	// 1. 加密新密码
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return 0, err
+	}
+
+	var userID int
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// 2. 校验并使用令牌
+		userToken, err := consumeUserToken(tx, token, constants.TokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+		userID = userToken.UserID
+
+		// 3. 更新密码
+		if err := tx.Model(&models.User{}).Where("id = ?", userToken.UserID).
+			Update("password_hash", hashedPassword).Error; err != nil {
+			return err
+		}
+
+		// 4. 注销所有会话，撤销个人访问令牌（密码泄露时攻击者可能已创建令牌）
+		if err := revokeSessionsExcept(tx, userToken.UserID, ""); err != nil {
+			return err
+		}
+		return revokePersonalAccessTokens(tx, userToken.UserID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	UserID          int    // 用户ID
+	SessionID       string // 当前会话ID（修改后保留当前会话）
+	CurrentPassword string // 当前密码
+	NewPassword     string // 新密码
+}
+
+// ChangePassword 修改密码，注销当前会话以外的所有会话，并撤销所有个人访问令牌
+func ChangePassword(req ChangePasswordRequest) error {
+	db := database.GetDB()
+
+	// 1. 查询用户
+	var user models.User
+	if err := db.First(&user, req.UserID).Error; err != nil {
+		return err
+	}
+
+	// 2. 验证当前密码
+	if err := utils.CheckPassword(user.PasswordHash, req.CurrentPassword); err != nil {
+		return fmt.Errorf("current password is incorrect")
+	}
+
+	// 3. 加密新密码
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 4. 更新密码
+		if err := tx.Model(&user).Update("password_hash", hashedPassword).Error; err != nil {
+			return err
+		}
+
+		// 5. 注销其他会话，撤销个人访问令牌
+		if err := revokeSessionsExcept(tx, user.ID, req.SessionID); err != nil {
+			return err
+		}
+		return revokePersonalAccessTokens(tx, user.ID)
+	})
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// rolePermissions 每个角色拥有的权限（普通用户只能操作自己的资源，不需要额外权限）
+var rolePermissions = map[string][]string{
+	constants.RoleUser: {},
+	constants.RoleAdmin: {
+		constants.PermissionManageAnyPost,
+		constants.PermissionManageUsers,
+		constants.PermissionModerate,
+		constants.PermissionViewAuditLog,
+		constants.PermissionViewStats,
+	},
+}
+
+// IsValidRole 判断角色是否合法
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Actor 发起操作的用户（用于权限验证）
+type Actor struct {
+	UserID    int    // 用户ID
+	Role      string // 用户角色
+	IP        string // 请求来源IP（记录审计日志）
+	RequestID string // 请求ID（记录审计日志）
+}
+
+// Can 判断操作者是否拥有某个权限
+func (a Actor) Can(permission string) bool {
+	for _, p := range rolePermissions[a.Role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// canManagePost 判断操作者能否修改该商品（商品所有者或拥有管理任意商品权限的角色）
+func (a Actor) canManagePost(post *models.Post) bool {
+	return post.UserID == a.UserID || a.Can(constants.PermissionManageAnyPost)
+}
+
+// canChangeHiddenPost 判断操作者能否修改被隐藏的商品（只有拥有审核权限的角色可以）
+// 被管理员隐藏后又被删除的商品仍保留 hidden_reason，同样不能由所有者重新上架
+func (a Actor) canChangeHiddenPost(post *models.Post) bool {
+	if post.Status != constants.PostStatusHidden && post.HiddenReason != constants.HiddenReasonModeration {
+		return true
+	}
+	return a.Can(constants.PermissionModerate)
+}
+
+// UpdateUserRole 修改用户角色（角色变更后注销该用户的所有会话，使旧令牌中的角色失效）
+func UpdateUserRole(actor Actor, userID int, role string) (*models.User, error) {
+	db := database.GetDB()
+
+	// 1. 验证权限和角色
+	if !actor.Can(constants.PermissionManageUsers) {
+		return nil, fmt.Errorf("unauthorized: you cannot manage users")
+	}
+	if !IsValidRole(role) {
+		return nil, fmt.Errorf("invalid role")
+	}
+
+	var user models.User
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 2. 先按 id 顺序锁定所有管理员，避免两个管理员同时互相降级后一个管理员都不剩
+		// （只锁目标用户再计数时，两个事务都会看到 2 个管理员）
+		var admins []models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("role = ?", constants.RoleAdmin).
+			Order("id").
+			Find(&admins).Error; err != nil {
+			return err
+		}
+
+		// 3. 查询用户
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+		if user.Role == role {
+			return nil
+		}
+
+		// 4. 至少保留一个管理员
+		if user.Role == constants.RoleAdmin && len(admins) <= 1 {
+			return fmt.Errorf("cannot remove the last admin")
+		}
+
+		// 5. 更新角色并注销会话
+		oldRole := user.Role
+		if err := tx.Model(&user).Update("role", role).Error; err != nil {
+			return err
+		}
+		if err := revokeSessionsExcept(tx, user.ID, ""); err != nil {
+			return err
+		}
+
+		// 6. 记录审计日志
+		return recordAudit(tx, actor, constants.AuditActionUserRoleChange, constants.AuditTargetUser, user.ID,
+			models.AuditState{"role": oldRole}, models.AuditState{"role": role})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// BootstrapAdmins 将配置中指定邮箱的用户设置为管理员（服务启动时调用，用于创建第一个管理员）
+func BootstrapAdmins(emails string) error {
+	db := database.GetDB()
+
+	var list []string
+	for _, email := range strings.Split(emails, ",") {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			list = append(list, email)
+		}
+	}
+	if len(list) == 0 {
+		return nil
+	}
+
+	return db.Model(&models.User{}).
+		Where("LOWER(email) IN ? AND role != ?", list, constants.RoleAdmin).
+		Update("role", constants.RoleAdmin).Error
+}
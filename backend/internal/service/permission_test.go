@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"backend/internal/constants"
+	"backend/internal/models"
+)
+
+func TestCanChangeHiddenPost(t *testing.T) {
+	owner := Actor{UserID: 1, Role: constants.RoleUser}
+	admin := Actor{UserID: 2, Role: constants.RoleAdmin}
+
+	tests := []struct {
+		name  string
+		post  models.Post
+		actor Actor
+		want  bool
+	}{
+		{name: "active post", post: models.Post{UserID: 1, Status: constants.PostStatusActive}, actor: owner, want: true},
+		{name: "deleted by owner", post: models.Post{UserID: 1, Status: "deleted"}, actor: owner, want: true},
+		{name: "hidden by moderators", post: models.Post{UserID: 1, Status: constants.PostStatusHidden, HiddenReason: constants.HiddenReasonModeration}, actor: owner, want: false},
+		{name: "hidden for suspension", post: models.Post{UserID: 1, Status: constants.PostStatusHidden, HiddenReason: constants.HiddenReasonSuspension}, actor: owner, want: false},
+		{name: "deleted after moderation", post: models.Post{UserID: 1, Status: "deleted", HiddenReason: constants.HiddenReasonModeration}, actor: owner, want: false},
+		{name: "moderator on hidden post", post: models.Post{UserID: 1, Status: constants.PostStatusHidden, HiddenReason: constants.HiddenReasonModeration}, actor: admin, want: true},
+		{name: "moderator on deleted after moderation", post: models.Post{UserID: 1, Status: "deleted", HiddenReason: constants.HiddenReasonModeration}, actor: admin, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.actor.canChangeHiddenPost(&tt.post); got != tt.want {
+				t.Fatalf("canChangeHiddenPost = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHiddenPostCannotBeRelistedThroughDelete(t *testing.T) {
+	owner := Actor{UserID: 1, Role: constants.RoleUser}
+	admin := Actor{UserID: 2, Role: constants.RoleAdmin}
+	post := models.Post{ID: 10, UserID: 1, Status: constants.PostStatusActive}
+
+	// 1. 管理员隐藏商品
+	post.Status = constants.PostStatusHidden
+	post.HiddenReason = constants.HiddenReasonModeration
+
+	// 2. 所有者不能删除（DELETE /item/{id}）
+	if owner.canChangeHiddenPost(&post) {
+		t.Fatalf("owner must not be able to delete a hidden post")
+	}
+
+	// 3. 管理员删除后 hidden_reason 保留，所有者仍然不能改回 active（PUT /item/{id}/status）
+	if !admin.canChangeHiddenPost(&post) {
+		t.Fatalf("moderator must be able to delete a hidden post")
+	}
+	post.Status = "deleted"
+	if owner.canChangeHiddenPost(&post) {
+		t.Fatalf("owner must not be able to relist a post deleted after moderation")
+	}
+
+	// 4. 管理员恢复后（hidden_reason 被清空）所有者可以正常管理
+	post.Status = constants.PostStatusActive
+	post.HiddenReason = ""
+	if !owner.canChangeHiddenPost(&post) {
+		t.Fatalf("owner must be able to manage the post after moderators restore it")
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// personalAccessTokenScopes 所有支持的权限范围
+var personalAccessTokenScopes = []string{
+	constants.ScopePostsRead,
+	constants.ScopePostsWrite,
+	constants.ScopeFavoritesRead,
+	constants.ScopeFavoritesWrite,
+	constants.ScopeNotificationsRead,
+}
+
+// isValidScope 判断权限范围是否合法
+func isValidScope(scope string) bool {
+	for _, s := range personalAccessTokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatePersonalAccessTokenRequest 创建个人访问令牌请求
+type CreatePersonalAccessTokenRequest struct {
+	UserID        int      // 用户ID
+	Name          string   // 令牌名称
+	Scopes        []string // 权限范围
+	ExpiresInDays int      // 有效期（天），0 表示使用默认值
+}
+
+// CreatedPersonalAccessToken 新建的个人访问令牌（明文只返回这一次）
+type CreatedPersonalAccessToken struct {
+	Token string `json:"token"`
+	models.PersonalAccessToken
+}
+
+// CreatePersonalAccessToken 创建个人访问令牌
+func CreatePersonalAccessToken(req CreatePersonalAccessTokenRequest) (*CreatedPersonalAccessToken, error) {
+	db := database.GetDB()
+
+	// 1. 验证名称、权限范围和有效期
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, fmt.Errorf("name must be between 1 and 100 characters")
+	}
+	if len(req.Scopes) == 0 {
+		return nil, fmt.Errorf("at least one scope is required")
+	}
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := make(map[string]bool, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		if !isValidScope(scope) {
+			return nil, fmt.Errorf("invalid scope: %s", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = constants.PersonalAccessTokenDefaultDays
+	}
+	if days < 1 || days > constants.PersonalAccessTokenMaxDays {
+		return nil, fmt.Errorf("expires_in_days must be between 1 and %d", constants.PersonalAccessTokenMaxDays)
+	}
+
+	// 2. 限制令牌数量
+	var count int64
+	if err := db.Model(&models.PersonalAccessToken{}).Where("user_id = ?", req.UserID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count >= constants.MaxPersonalAccessTokens {
+		return nil, fmt.Errorf("too many personal access tokens")
+	}
+
+	// 3. 生成令牌（数据库只保存哈希值）
+	random, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	plain := constants.PersonalAccessTokenPrefix + random
+	expiresAt := time.Now().AddDate(0, 0, days)
+	token := models.PersonalAccessToken{
+		UserID:      req.UserID,
+		Name:        name,
+		TokenPrefix: plain[:len(constants.PersonalAccessTokenPrefix)+6],
+		TokenHash:   utils.HashToken(plain),
+		Scopes:      scopes,
+		ExpiresAt:   &expiresAt,
+	}
+	if err := db.Create(&token).Error; err != nil {
+		return nil, err
+	}
+
+	return &CreatedPersonalAccessToken{Token: plain, PersonalAccessToken: token}, nil
+}
+
+// GetPersonalAccessTokens 获取我的个人访问令牌列表
+func GetPersonalAccessTokens(userID int) ([]models.PersonalAccessToken, error) {
+	db := database.GetDB()
+
+	tokens := []models.PersonalAccessToken{}
+	if err := db.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// DeletePersonalAccessToken 删除（撤销）个人访问令牌
+func DeletePersonalAccessToken(userID int, tokenID int) error {
+	db := database.GetDB()
+
+	result := db.Where("id = ? AND user_id = ?", tokenID, userID).Delete(&models.PersonalAccessToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// revokePersonalAccessTokens 撤销用户的所有个人访问令牌（重置密码、退出所有设备时调用）
+func revokePersonalAccessTokens(tx *gorm.DB, userID int) error {
+	return tx.Where("user_id = ?", userID).Delete(&models.PersonalAccessToken{}).Error
+}
+
+// AuthenticatePersonalAccessToken 验证个人访问令牌，返回令牌和所属用户
+func AuthenticatePersonalAccessToken(plain string) (*models.PersonalAccessToken, *models.User, error) {
+	db := database.GetDB()
+
+	// 1. 查找令牌
+	var token models.PersonalAccessToken
+	if err := db.Where("token_hash = ?", utils.HashToken(plain)).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, fmt.Errorf("invalid personal access token")
+		}
+		return nil, nil, err
+	}
+	if token.ExpiresAt != nil && time.Now().After(*token.ExpiresAt) {
+		return nil, nil, fmt.Errorf("invalid personal access token")
+	}
+
+	// 2. 查询所属用户
+	user, err := GetUserByID(token.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 3. 更新最近使用时间（间隔内只更新一次）
+	now := time.Now()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > constants.PersonalAccessTokenLastUsedMinutes*time.Minute {
+		if err := db.Model(&token).Update("last_used_at", now).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return &token, user, nil
+}
@@ -2,13 +2,18 @@ package service
 
 import (
 	"fmt"
+	"time"
 
+	"backend/internal/constants"
 	"backend/internal/database"
 	"backend/internal/models"
+
+	"gorm.io/gorm"
 )
 
 // GetPostsRequest 获取商品列表请求参数
 type GetPostsRequest struct {
+	ViewerID int // 当前用户ID（用于判断是否已收藏）
 	Page     int // 页码，从1开始
 	PageSize int // 每页数量
 }
@@ -37,9 +42,9 @@ func GetPosts(req GetPostsRequest) (*GetPostsResponse, error) {
 	// 2. 计算偏移量
 	offset := (req.Page - 1) * req.PageSize
 
-	// 3. 查询总数量
+	// 3. 查询总数量（不包括屏蔽了当前用户的卖家的商品）
 	var totalCount int64
-	if err := db.Model(&models.Post{}).
+	if err := excludeBlockedSellers(db.Model(&models.Post{}), req.ViewerID).
 		Where("status = ?", "active"). // 只查询状态为active的商品
 		Count(&totalCount).Error; err != nil {
 		return nil, err
@@ -47,7 +52,7 @@ func GetPosts(req GetPostsRequest) (*GetPostsResponse, error) {
 
 	// 4. 查询分页数据（包含用户信息）
 	var posts []models.Post
-	if err := db.Preload("User"). // 预加载用户信息
+	if err := excludeBlockedSellers(db.Preload("User"), req.ViewerID). // 预加载用户信息
 		Where("status = ?", "active").
 		Order("created_at DESC"). // 按创建时间倒序
 		Limit(req.PageSize).
@@ -56,7 +61,12 @@ func GetPosts(req GetPostsRequest) (*GetPostsResponse, error) {
 		return nil, err
 	}
 
-	// 5. 计算总页数
+	// 5. 补充卖家评分、收藏信息等附加信息
+	if err := enrichPosts(posts, req.ViewerID); err != nil {
+		return nil, err
+	}
+
+	// 6. 计算总页数
 	totalPages := int(totalCount) / req.PageSize
 	if int(totalCount)%req.PageSize != 0 {
 		totalPages++
@@ -72,19 +82,45 @@ func GetPosts(req GetPostsRequest) (*GetPostsResponse, error) {
 }
 
 // GetPostByID 根据ID获取商品详情
-func GetPostByID(postID int) (*models.Post, error) {
+// viewerID 为当前用户ID，用于判断是否已收藏
+func GetPostByID(postID int, viewerID int) (*models.Post, error) {
 	db := database.GetDB()
 
 	var post models.Post
-	// 查询指定ID的商品，并预加载用户信息
+	// 查询指定ID的商品，并预加载用户信息和价格变动记录
 	// 只允许查看未删除的商品（active和sold状态都可以查看，但deleted不行）
 	if err := db.Preload("User").
+		Preload("PriceHistory", func(db *gorm.DB) *gorm.DB {
+			return db.Order("changed_at ASC")
+		}).
 		Where("id = ? AND status != ?", postID, "deleted").
 		First(&post).Error; err != nil {
 		return nil, err
 	}
 
-	return &post, nil
+	// 被管理员隐藏的商品只有卖家本人可以查看
+	if post.Status == constants.PostStatusHidden && post.UserID != viewerID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	// 补充卖家评分、收藏信息等附加信息
+	posts := []models.Post{post}
+	if err := enrichPosts(posts, viewerID); err != nil {
+		return nil, err
+	}
+
+	return &posts[0], nil
+}
+
+// enrichPosts 为商品列表补充非数据库字段（卖家评分、收藏信息等），并隐藏联系方式
+func enrichPosts(posts []models.Post, viewerID int) error {
+	if err := attachSellerRatings(posts); err != nil {
+		return err
+	}
+	if err := attachFavoriteInfo(posts, viewerID); err != nil {
+		return err
+	}
+	return maskContactInfo(posts, viewerID)
 }
 
 // GetMyListingsRequest 获取我的商品列表请求参数
@@ -137,7 +173,12 @@ func GetMyListings(req GetMyListingsRequest) (*GetMyListingsResponse, error) {
 		return nil, err
 	}
 
-	// 5. 计算总页数
+	// 5. 补充卖家评分、收藏信息等附加信息
+	if err := enrichPosts(posts, req.UserID); err != nil {
+		return nil, err
+	}
+
+	// 6. 计算总页数
 	totalPages := int(totalCount) / req.PageSize
 	if int(totalCount)%req.PageSize != 0 {
 		totalPages++
@@ -153,7 +194,7 @@ func GetMyListings(req GetMyListingsRequest) (*GetMyListingsResponse, error) {
 }
 
 // DeletePost 删除商品（软删除，只修改状态）
-func DeletePost(postID int, userID int) error {
+func DeletePost(postID int, actor Actor) error {
 	db := database.GetDB()
 
 	// 1. 先查询该商品是否存在
@@ -162,18 +203,26 @@ func DeletePost(postID int, userID int) error {
 		return err // 商品不存在
 	}
 
-	// 2. 验证该商品是否属于当前用户
-	if post.UserID != userID {
+	// 2. 验证该商品是否属于当前用户（管理员可以删除任意商品）
+	if !actor.canManagePost(&post) {
 		return fmt.Errorf("unauthorized: you can only delete your own posts")
 	}
 
-	// 3. 软删除：只修改 status 为 "deleted"
-	// UpdatedAt 会自动更新（因为模型中有 gorm:"autoUpdateTime" 标签）
-	if err := db.Model(&post).Update("status", "deleted").Error; err != nil {
-		return err
+	// 3. 被管理员隐藏的商品只有拥有审核权限的角色可以删除（删除时保留 hidden_reason）
+	if !actor.canChangeHiddenPost(&post) {
+		return fmt.Errorf("unauthorized: post is hidden by moderators")
 	}
 
-	return nil
+	// 4. 软删除：只修改 status 为 "deleted"，并记录审计日志
+	// UpdatedAt 会自动更新（因为模型中有 gorm:"autoUpdateTime" 标签）
+	oldStatus := post.Status
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&post).Update("status", "deleted").Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, actor, constants.AuditActionPostDelete, constants.AuditTargetPost, post.ID,
+			models.AuditState{"status": oldStatus}, models.AuditState{"status": post.Status})
+	})
 }
 
 // CreatePostRequest 创建商品请求
@@ -184,6 +233,7 @@ type CreatePostRequest struct {
 	Price       float64  // 价格
 	ContactInfo string   // 联系方式
 	ZipCode     string   // 邮编
+	Category    string   // 分类，为空时使用 other
 	Negotiable  bool     // 是否可议价
 	ImageURLs   []string // 图片URL数组
 }
@@ -193,6 +243,10 @@ func CreatePost(req CreatePostRequest) (*models.Post, error) {
 	db := database.GetDB()
 
 	// 1. 创建 Post 对象
+	category := req.Category
+	if category == "" {
+		category = constants.PostCategoryOther
+	}
 	post := models.Post{
 		UserID:      req.UserID,
 		Title:       req.Title,
@@ -200,13 +254,19 @@ func CreatePost(req CreatePostRequest) (*models.Post, error) {
 		Price:       req.Price,
 		ContactInfo: req.ContactInfo,
 		ZipCode:     req.ZipCode,
+		Category:    category,
 		Negotiable:  req.Negotiable,
 		ImageURLs:   req.ImageURLs,
 		Status:      "active", // 默认状态为 active
 	}
 
-	// 2. 保存到数据库
-	if err := db.Create(&post).Error; err != nil {
+	// 2. 保存到数据库，并写入关注者的动态
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&post).Error; err != nil {
+			return err
+		}
+		return fanOutPostToFollowers(tx, &post)
+	}); err != nil {
 		return nil, err
 	}
 
@@ -221,13 +281,15 @@ func CreatePost(req CreatePostRequest) (*models.Post, error) {
 // UpdatePostRequest 更新商品请求
 type UpdatePostRequest struct {
 	PostID      int     // 商品ID
-	UserID      int     // 用户ID（用于权限验证）
+	Actor       Actor   // 操作者（用于权限验证）
 	Title       string  // 标题
 	Description string  // 描述
 	Price       float64 // 价格
+	Category    string  // 分类，为空时不修改
 }
 
-// UpdatePost 更新商品信息（只允许修改title, description, price）
+// UpdatePost 更新商品信息（只允许修改title, description, price, category）
+// 价格变动会记录到价格历史，降价时通知收藏了该商品的用户
 func UpdatePost(req UpdatePostRequest) (*models.Post, error) {
 	db := database.GetDB()
 
@@ -237,23 +299,58 @@ func UpdatePost(req UpdatePostRequest) (*models.Post, error) {
 		return nil, err // 商品不存在
 	}
 
-	// 2. 验证该商品是否属于当前用户
-	if post.UserID != req.UserID {
+	// 2. 验证该商品是否属于当前用户（管理员可以编辑任意商品）
+	if !req.Actor.canManagePost(&post) {
 		return nil, fmt.Errorf("unauthorized: you can only edit your own posts")
 	}
 
-	// 3. 更新允许修改的字段
+	// 3. 被管理员隐藏的商品只有拥有审核权限的角色可以编辑
+	if !req.Actor.canChangeHiddenPost(&post) {
+		return nil, fmt.Errorf("unauthorized: post is hidden by moderators")
+	}
+
+	// 4. 更新允许修改的字段
 	updates := map[string]interface{}{
 		"title":       req.Title,
 		"description": req.Description,
 		"price":       req.Price,
 	}
+	if req.Category != "" {
+		updates["category"] = req.Category
+	}
 
-	if err := db.Model(&post).Updates(updates).Error; err != nil {
+	oldPrice := post.Price
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&post).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		// 价格没有变化时不需要记录
+		if req.Price == oldPrice {
+			return nil
+		}
+		history := models.PriceHistory{
+			PostID:   post.ID,
+			OldPrice: oldPrice,
+			NewPrice: req.Price,
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return err
+		}
+
+		// 在售商品降价时通知收藏者（隐藏、已售出、已删除的商品不通知）
+		if req.Price < oldPrice && post.Status == constants.PostStatusActive {
+			post.Title = req.Title
+			post.Price = req.Price
+			return notifyPriceDrop(tx, &post, oldPrice)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// 4. 重新加载更新后的数据（包含用户信息）
+	// 5. 重新加载更新后的数据（包含用户信息）
 	if err := db.Preload("User").First(&post, req.PostID).Error; err != nil {
 		return nil, err
 	}
@@ -263,9 +360,10 @@ func UpdatePost(req UpdatePostRequest) (*models.Post, error) {
 
 // UpdatePostStatusRequest 更新商品状态请求
 type UpdatePostStatusRequest struct {
-	PostID int    // 商品ID
-	UserID int    // 用户ID（用于权限验证）
-	Status string // 新状态（如 "sold"）
+	PostID        int    // 商品ID
+	Actor         Actor  // 操作者（用于权限验证）
+	Status        string // 新状态（如 "sold"）
+	BuyerUsername string // 买家用户名（可选，标记为已售出时用于生成交易记录）
 }
 
 // UpdatePostStatus 更新商品状态（例如标记为已售出）
@@ -278,12 +376,17 @@ func UpdatePostStatus(req UpdatePostStatusRequest) (*models.Post, error) {
 		return nil, err // 商品不存在
 	}
 
-	// 2. 验证该商品是否属于当前用户
-	if post.UserID != req.UserID {
+	// 2. 验证该商品是否属于当前用户（管理员可以修改任意商品）
+	if !req.Actor.canManagePost(&post) {
 		return nil, fmt.Errorf("unauthorized: you can only update your own posts")
 	}
 
-	// 3. 验证状态值是否合法
+	// 3. 被管理员隐藏的商品（包括隐藏后被删除的）只有拥有审核权限的角色可以修改状态
+	if !req.Actor.canChangeHiddenPost(&post) {
+		return nil, fmt.Errorf("unauthorized: post is hidden by moderators")
+	}
+
+	// 4. 验证状态值是否合法
 	validStatuses := map[string]bool{
 		"active":  true,
 		"sold":    true,
@@ -293,12 +396,46 @@ func UpdatePostStatus(req UpdatePostStatusRequest) (*models.Post, error) {
 		return nil, fmt.Errorf("invalid status: must be one of active, sold, deleted")
 	}
 
-	// 4. 更新状态
-	if err := db.Model(&post).Update("status", req.Status).Error; err != nil {
+	// 5. 更新状态（标记为已售出并指定了买家时，同时生成交易记录）
+	oldStatus := post.Status
+	before := models.AuditState{"status": post.Status, "hidden_reason": post.HiddenReason}
+	updates := map[string]interface{}{"status": req.Status, "hidden_reason": ""}
+	switch {
+	case req.Status == constants.PostStatusSold && oldStatus != constants.PostStatusSold:
+		updates["sold_at"] = time.Now() // 记录售出时间（用于统计售出用时）
+	case req.Status == constants.PostStatusActive:
+		updates["sold_at"] = nil
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&post).Updates(updates).Error; err != nil {
+			return err
+		}
+		after := models.AuditState{"status": req.Status, "hidden_reason": ""}
+		if req.BuyerUsername != "" {
+			after["buyer_username"] = req.BuyerUsername
+		}
+		if err := recordAudit(tx, req.Actor, constants.AuditActionPostStatusChange, constants.AuditTargetPost, post.ID, before, after); err != nil {
+			return err
+		}
+		if req.Status != constants.PostStatusSold {
+			return nil
+		}
+		if req.BuyerUsername != "" {
+			if err := createTransaction(tx, &post, req.BuyerUsername); err != nil {
+				return err
+			}
+		}
+		// 首次标记为已售出时通知收藏者
+		if oldStatus != constants.PostStatusSold {
+			return notifyItemSold(tx, &post)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// 5. 重新加载更新后的数据（包含用户信息）
+	// 6. 重新加载更新后的数据（包含用户信息）
 	if err := db.Preload("User").First(&post, req.PostID).Error; err != nil {
 		return nil, err
 	}
@@ -307,3 +444,25 @@ func UpdatePostStatus(req UpdatePostStatusRequest) (*models.Post, error) {
 }
 
 // TODO: 实现其他商品相关的业务逻辑
+
+// postCategories 所有支持的商品分类
+var postCategories = []string{
+	constants.PostCategoryElectronics,
+	constants.PostCategoryFurniture,
+	constants.PostCategoryClothing,
+	constants.PostCategoryBooks,
+	constants.PostCategorySports,
+	constants.PostCategoryHome,
+	constants.PostCategoryVehicles,
+	constants.PostCategoryOther,
+}
+
+// IsValidPostCategory 判断商品分类是否合法
+func IsValidPostCategory(category string) bool {
+	for _, c := range postCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
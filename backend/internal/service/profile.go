@@ -0,0 +1,250 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/email"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProfileResponse 我的账号信息（包含只对本人可见的字段）
+type ProfileResponse struct {
+	models.User
+	PendingEmail              string     `json:"pending_email,omitempty"`                // 等待验证的新邮箱
+	UsernameChangeAvailableAt *time.Time `json:"username_change_available_at,omitempty"` // 下次可以修改用户名的时间
+}
+
+// GetProfile 获取我的账号信息
+func GetProfile(userID int) (*ProfileResponse, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &ProfileResponse{User: *user, PendingEmail: user.PendingEmail}
+	if user.UsernameChangedAt != nil {
+		availableAt := user.UsernameChangedAt.AddDate(0, 0, constants.UsernameChangeCooldownDays)
+		if availableAt.After(time.Now()) {
+			profile.UsernameChangeAvailableAt = &availableAt
+		}
+	}
+	return profile, nil
+}
+
+// UpdateProfileRequest 更新个人资料请求（nil 表示不修改）
+type UpdateProfileRequest struct {
+	UserID           int     // 用户ID
+	DisplayName      *string // 昵称
+	Bio              *string // 个人简介
+	PreferredContact *string // 首选联系方式
+	Username         *string // 用户名（有修改间隔限制）
+	Email            *string // 新邮箱（验证后才生效）
+	CurrentPassword  string  // 当前密码（修改邮箱时需要，第三方登录用户刚登录时可以为空）
+	SessionID        string  // 当前会话ID（第三方登录用户不输入密码时确认是最近登录）
+}
+
+// UpdateProfile 更新个人资料
+// 修改邮箱时先保存为待验证邮箱，并向新邮箱发送验证邮件，验证通过后才替换原邮箱
+func UpdateProfile(req UpdateProfileRequest) (*ProfileResponse, error) {
+	db := database.GetDB()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 1. 查询用户并加锁
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, req.UserID).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if req.DisplayName != nil {
+			updates["display_name"] = *req.DisplayName
+		}
+		if req.Bio != nil {
+			updates["bio"] = *req.Bio
+		}
+		if req.PreferredContact != nil {
+			updates["preferred_contact"] = *req.PreferredContact
+		}
+
+		// 2. 修改用户名（限制修改频率）
+		if req.Username != nil && *req.Username != user.Username {
+			if user.UsernameChangedAt != nil &&
+				time.Now().Before(user.UsernameChangedAt.AddDate(0, 0, constants.UsernameChangeCooldownDays)) {
+				return fmt.Errorf("username was changed recently")
+			}
+			var count int64
+			if err := tx.Model(&models.User{}).Where("username = ? AND id != ?", *req.Username, user.ID).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("username already taken")
+			}
+			updates["username"] = *req.Username
+			updates["username_changed_at"] = time.Now()
+		}
+
+		// 3. 修改邮箱（需要当前密码或最近登录，并验证新邮箱）
+		if req.Email != nil && !strings.EqualFold(*req.Email, user.Email) {
+			if err := verifyReauthentication(tx, &user, req.SessionID, req.CurrentPassword); err != nil {
+				return err
+			}
+			if err := requestEmailChange(tx, &user, *req.Email); err != nil {
+				return err
+			}
+		}
+
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&user).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return GetProfile(req.UserID)
+}
+
+// requestEmailChange 保存待验证的新邮箱，并向新邮箱发送验证邮件（需在数据库事务中调用）
+func requestEmailChange(tx *gorm.DB, user *models.User, newEmail string) error {
+	// 1. 新邮箱不能已被其他账号使用
+	if err := checkEmailAvailable(tx, user.ID, newEmail); err != nil {
+		return err
+	}
+
+	// 2. 限制发送频率
+	if err := checkTokenResendCooldown(tx, user.ID, constants.TokenPurposeEmailChange); err != nil {
+		return err
+	}
+
+	// 3. 保存待验证邮箱并发送验证邮件
+	if err := tx.Model(user).Update("pending_email", newEmail).Error; err != nil {
+		return err
+	}
+	ttl := constants.EmailChangeExpiryHours * time.Hour
+	token, err := createUserToken(tx, user.ID, constants.TokenPurposeEmailChange, ttl)
+	if err != nil {
+		return err
+	}
+	return EnqueueEmail(tx, newEmail, email.TemplateEmailChange, map[string]interface{}{
+		"Username":  user.Username,
+		"Link":      config.AppConfig.AppBaseURL + "/confirm-email-change?token=" + url.QueryEscape(token),
+		"ExpiresIn": fmt.Sprintf("%d hours", constants.EmailChangeExpiryHours),
+	})
+}
+
+// ConfirmEmailChange 使用验证令牌确认修改邮箱，注销所有会话并通知原邮箱
+func ConfirmEmailChange(token string) (*models.User, error) {
+	db := database.GetDB()
+
+	var user models.User
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 1. 校验并使用令牌
+		userToken, err := consumeUserToken(tx, token, constants.TokenPurposeEmailChange)
+		if err != nil {
+			return err
+		}
+
+		// 2. 查询待验证的邮箱
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userToken.UserID).Error; err != nil {
+			return err
+		}
+		if user.PendingEmail == "" {
+			return fmt.Errorf("invalid or expired token")
+		}
+
+		// 3. 发送验证邮件后该邮箱可能已被其他账号注册
+		if err := checkEmailAvailable(tx, user.ID, user.PendingEmail); err != nil {
+			return err
+		}
+
+		// 4. 替换邮箱
+		oldEmail, newEmail := user.Email, user.PendingEmail
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"email":          newEmail,
+			"email_verified": true,
+			"pending_email":  "",
+		}).Error; err != nil {
+			return err
+		}
+
+		// 5. 邮箱是登录账号：注销所有会话并撤销个人访问令牌
+		if err := revokeSessionsExcept(tx, user.ID, ""); err != nil {
+			return err
+		}
+		if err := revokePersonalAccessTokens(tx, user.ID); err != nil {
+			return err
+		}
+
+		// 6. 通知原邮箱（账号被盗时原主人可以及时发现）
+		return EnqueueEmail(tx, oldEmail, email.TemplateEmailChanged, map[string]interface{}{
+			"Username":  user.Username,
+			"NewEmail":  newEmail,
+			"ChangedAt": time.Now().UTC().Format("2006-01-02 15:04 UTC"),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// checkEmailAvailable 检查邮箱是否未被其他账号使用
+func checkEmailAvailable(tx *gorm.DB, userID int, emailAddress string) error {
+	var count int64
+	if err := tx.Model(&models.User{}).Where("LOWER(email) = LOWER(?) AND id != ?", emailAddress, userID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("email already in use")
+	}
+	return nil
+}
+
+// UpdateAvatar 更新头像地址，并删除之前上传的头像文件
+func UpdateAvatar(userID int, avatarURL string) (*ProfileResponse, error) {
+	db := database.GetDB()
+
+	// 1. 查询之前的头像
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	oldAvatarURL := user.AvatarURL
+
+	// 2. 更新头像地址
+	if err := db.Model(&user).Update("avatar_url", avatarURL).Error; err != nil {
+		return nil, err
+	}
+
+	// 3. 删除旧头像文件（失败不影响结果）
+	if filename, ok := database.FileNameFromURL(oldAvatarURL); ok && oldAvatarURL != avatarURL {
+		if err := database.DeleteFile(filename); err != nil {
+			log.Printf("⚠️  Failed to delete old avatar %s: %v", filename, err)
+		}
+	}
+
+	return GetProfile(userID)
+}
+
+// IsValidContactMethod 判断首选联系方式是否合法（空字符串表示未设置）
+func IsValidContactMethod(method string) bool {
+	switch method {
+	case "", constants.ContactMethodEmail, constants.ContactMethodPhone, constants.ContactMethodText:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SubmitReviewRequest 提交评价请求
+type SubmitReviewRequest struct {
+	TransactionID int    // 交易ID
+	ReviewerID    int    // 评价者ID
+	Rating        int    // 评分 1-5
+	Comment       string // 评价内容
+}
+
+// SubmitReview 提交交易评价
+// 评价在双方都提交后立即公开，否则在评价窗口结束时自动公开
+func SubmitReview(req SubmitReviewRequest) (*models.Review, error) {
+	db := database.GetDB()
+
+	// 1. 验证评分范围
+	if req.Rating < constants.MinRating || req.Rating > constants.MaxRating {
+		return nil, fmt.Errorf("invalid rating: must be between %d and %d", constants.MinRating, constants.MaxRating)
+	}
+
+	// 2. 查询交易是否存在
+	var transaction models.Transaction
+	if err := db.First(&transaction, req.TransactionID).Error; err != nil {
+		return nil, err // 交易不存在
+	}
+
+	// 3. 确认评价者是交易的一方，并确定被评价者
+	review := models.Review{
+		TransactionID: transaction.ID,
+		ReviewerID:    req.ReviewerID,
+		Rating:        req.Rating,
+		Comment:       req.Comment,
+	}
+	switch req.ReviewerID {
+	case transaction.BuyerID:
+		review.Role = constants.ReviewRoleBuyer
+		review.RevieweeID = transaction.SellerID
+	case transaction.SellerID:
+		review.Role = constants.ReviewRoleSeller
+		review.RevieweeID = transaction.BuyerID
+	default:
+		return nil, fmt.Errorf("unauthorized: you are not part of this transaction")
+	}
+
+	// 4. 检查评价窗口是否已结束
+	deadline := transaction.CreatedAt.AddDate(0, 0, constants.ReviewWindowDays)
+	if time.Now().After(deadline) {
+		return nil, fmt.Errorf("review window has closed")
+	}
+	review.PublishAt = deadline
+
+	// 5. 保存评价；如果对方已经评价，双方的评价立即公开
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 锁定交易记录，避免双方同时提交时都看不到对方的评价
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Transaction{}, transaction.ID).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.Review{}).
+			Where("transaction_id = ? AND reviewer_id = ?", transaction.ID, req.ReviewerID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("review already submitted")
+		}
+
+		var counterpart models.Review
+		err := tx.Where("transaction_id = ? AND reviewer_id = ?", transaction.ID, review.RevieweeID).First(&counterpart).Error
+		if err == nil {
+			now := time.Now()
+			review.PublishAt = now
+			if err := tx.Model(&counterpart).Update("publish_at", now).Error; err != nil {
+				return err
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+
+		// 通知被评价者（评价内容在公开前不会展示；双方都已评价时立即公开）
+		body := "It will be published once you review them back or the review window ends."
+		if !review.PublishAt.After(time.Now()) {
+			body = "Both reviews are now published."
+		}
+		postID := transaction.PostID
+		return Notify(tx, NotificationEvent{
+			UserID: review.RevieweeID,
+			Type:   constants.NotificationTypeReviewLeft,
+			Title:  "You received a new review",
+			Body:   body,
+			PostID: &postID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// GetUserReviewsRequest 获取用户收到的评价请求参数
+type GetUserReviewsRequest struct {
+	UserID   int    // 被评价的用户ID
+	As       string // 可选：只看作为 seller 或 buyer 收到的评价
+	Page     int    // 页码，从1开始
+	PageSize int    // 每页数量
+}
+
+// ReviewItem 评价列表中的单条评价（只包含评价者的公开信息）
+type ReviewItem struct {
+	ID               int       `json:"id"`
+	TransactionID    int       `json:"transaction_id"`
+	Role             string    `json:"role"` // 评价者在交易中的身份
+	Rating           int       `json:"rating"`
+	Comment          string    `json:"comment"`
+	ReviewerID       int       `json:"reviewer_id"`
+	ReviewerUsername string    `json:"reviewer_username"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// GetUserReviewsResponse 获取用户收到的评价响应
+type GetUserReviewsResponse struct {
+	Reviews      []ReviewItem         `json:"reviews"`
+	SellerRating models.RatingSummary `json:"seller_rating"` // 作为卖家的评分汇总
+	TotalCount   int64                `json:"total_count"`   // 总数量
+	Page         int                  `json:"page"`          // 当前页码
+	PageSize     int                  `json:"page_size"`     // 每页数量
+	TotalPages   int                  `json:"total_pages"`   // 总页数
+}
+
+// GetUserReviews 获取用户收到的已公开评价（分页）
+func GetUserReviews(req GetUserReviewsRequest) (*GetUserReviewsResponse, error) {
+	db := database.GetDB()
+
+	// 1. 设置默认值
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	// 2. 作为卖家收到的评价由买家写出，反之亦然
+	var reviewerRole string
+	switch req.As {
+	case "":
+	case constants.ReviewRoleSeller:
+		reviewerRole = constants.ReviewRoleBuyer
+	case constants.ReviewRoleBuyer:
+		reviewerRole = constants.ReviewRoleSeller
+	default:
+		return nil, fmt.Errorf("invalid role: must be seller or buyer")
+	}
+
+	// 3. 构建查询（只包含已公开的评价）
+	now := time.Now()
+	baseQuery := func() *gorm.DB {
+		query := db.Table("reviews").
+			Joins("JOIN users ON users.id = reviews.reviewer_id").
+			Where("reviews.reviewee_id = ? AND reviews.publish_at <= ?", req.UserID, now)
+		if reviewerRole != "" {
+			query = query.Where("reviews.role = ?", reviewerRole)
+		}
+		return query
+	}
+
+	// 4. 查询总数量
+	var totalCount int64
+	if err := baseQuery().Count(&totalCount).Error; err != nil {
+		return nil, err
+	}
+
+	// 5. 查询分页数据
+	reviews := []ReviewItem{}
+	if err := baseQuery().
+		Select("reviews.id, reviews.transaction_id, reviews.role, reviews.rating, reviews.comment, " +
+			"reviews.reviewer_id, users.username AS reviewer_username, reviews.created_at").
+		Order("reviews.created_at DESC").
+		Limit(req.PageSize).
+		Offset((req.Page - 1) * req.PageSize).
+		Scan(&reviews).Error; err != nil {
+		return nil, err
+	}
+
+	// 6. 查询评分汇总
+	ratings, err := getSellerRatings([]int{req.UserID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetUserReviewsResponse{
+		Reviews:      reviews,
+		SellerRating: ratings[req.UserID],
+		TotalCount:   totalCount,
+		Page:         req.Page,
+		PageSize:     req.PageSize,
+		TotalPages:   calcTotalPages(totalCount, req.PageSize),
+	}, nil
+}
+
+// getSellerRatings 批量查询用户作为卖家的评分汇总（只统计已公开的评价）
+func getSellerRatings(userIDs []int) (map[int]models.RatingSummary, error) {
+	db := database.GetDB()
+
+	ratings := make(map[int]models.RatingSummary)
+	if len(userIDs) == 0 {
+		return ratings, nil
+	}
+
+	var rows []struct {
+		RevieweeID int
+		Average    float64
+		Count      int64
+	}
+	if err := db.Model(&models.Review{}).
+		Select("reviewee_id, AVG(rating) AS average, COUNT(*) AS count").
+		Where("reviewee_id IN ? AND role = ? AND publish_at <= ?", userIDs, constants.ReviewRoleBuyer, time.Now()).
+		Group("reviewee_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		ratings[row.RevieweeID] = models.RatingSummary{
+			Average: math.Round(row.Average*100) / 100, // 保留两位小数
+			Count:   row.Count,
+		}
+	}
+	return ratings, nil
+}
+
+// attachSellerRatings 为商品的发布者附加卖家评分汇总
+func attachSellerRatings(posts []models.Post) error {
+	userIDs := make([]int, 0, len(posts))
+	seen := make(map[int]bool)
+	for _, post := range posts {
+		if !seen[post.UserID] {
+			seen[post.UserID] = true
+			userIDs = append(userIDs, post.UserID)
+		}
+	}
+
+	ratings, err := getSellerRatings(userIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range posts {
+		rating := ratings[posts[i].UserID]
+		posts[i].User.SellerRating = &rating
+	}
+	return nil
+}
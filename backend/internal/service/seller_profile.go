@@ -0,0 +1,134 @@
+package service
+
+import (
+	"time"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// GetSellerProfileRequest 获取卖家公开主页请求参数
+type GetSellerProfileRequest struct {
+	Username string // 用户名
+	Page     int    // 页码，从1开始
+	PageSize int    // 每页数量
+}
+
+// SellerPostItem 卖家主页中的在售商品（不包含发布者信息）
+type SellerPostItem struct {
+	ID          int            `json:"id"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Price       float64        `json:"price"`
+	ZipCode     string         `json:"zip_code"`
+	Negotiable  bool           `json:"negotiable"`
+	ImageURLs   pq.StringArray `json:"image_urls"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// SellerProfileResponse 卖家公开主页（只包含公开信息，不包含邮箱等私密字段）
+type SellerProfileResponse struct {
+	ID                 int                  `json:"id"`
+	Username           string               `json:"username"`
+	DisplayName        string               `json:"display_name"`
+	Bio                string               `json:"bio"`
+	AvatarURL          string               `json:"avatar_url"`
+	JoinedAt           time.Time            `json:"joined_at"`            // 注册时间
+	ActiveListingCount int64                `json:"active_listing_count"` // 在售商品数量
+	SoldCount          int64                `json:"sold_count"`           // 已售出商品数量
+	FollowerCount      int64                `json:"follower_count"`       // 关注者数量
+	SellerRating       models.RatingSummary `json:"seller_rating"`        // 作为卖家的评分汇总
+	Posts              []SellerPostItem     `json:"posts"`                // 在售商品（分页）
+	TotalCount         int64                `json:"total_count"`          // 在售商品总数量
+	Page               int                  `json:"page"`                 // 当前页码
+	PageSize           int                  `json:"page_size"`            // 每页数量
+	TotalPages         int                  `json:"total_pages"`          // 总页数
+}
+
+// GetSellerProfile 获取卖家公开主页（基本信息、统计数据和分页的在售商品）
+func GetSellerProfile(req GetSellerProfileRequest) (*SellerProfileResponse, error) {
+	db := database.GetDB()
+
+	// 1. 设置默认值
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 8
+	}
+
+	// 2. 查询用户（已删除的账号不展示）
+	user, err := GetUserByUsername(req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if user.AnonymizedAt != nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	// 3. 统计在售和已售出的商品数量
+	var counts []struct {
+		Status string
+		Count  int64
+	}
+	if err := db.Model(&models.Post{}).
+		Select("status, COUNT(*) AS count").
+		Where("user_id = ? AND status IN ?", user.ID, []string{constants.PostStatusActive, constants.PostStatusSold}).
+		Group("status").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	var activeCount, soldCount int64
+	for _, c := range counts {
+		switch c.Status {
+		case constants.PostStatusActive:
+			activeCount = c.Count
+		case constants.PostStatusSold:
+			soldCount = c.Count
+		}
+	}
+
+	// 4. 查询分页的在售商品
+	posts := []SellerPostItem{}
+	if err := db.Model(&models.Post{}).
+		Where("user_id = ? AND status = ?", user.ID, constants.PostStatusActive).
+		Select("id, title, description, price, zip_code, negotiable, image_urls, created_at").
+		Order("created_at DESC").
+		Limit(req.PageSize).
+		Offset((req.Page - 1) * req.PageSize).
+		Scan(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	// 5. 查询评分汇总和关注者数量
+	ratings, err := getSellerRatings([]int{user.ID})
+	if err != nil {
+		return nil, err
+	}
+	followerCount, err := getFollowerCount(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SellerProfileResponse{
+		ID:                 user.ID,
+		Username:           user.Username,
+		DisplayName:        user.DisplayName,
+		Bio:                user.Bio,
+		AvatarURL:          user.AvatarURL,
+		JoinedAt:           user.CreatedAt,
+		ActiveListingCount: activeCount,
+		SoldCount:          soldCount,
+		FollowerCount:      followerCount,
+		SellerRating:       ratings[user.ID],
+		Posts:              posts,
+		TotalCount:         activeCount,
+		Page:               req.Page,
+		PageSize:           req.PageSize,
+		TotalPages:         calcTotalPages(activeCount, req.PageSize),
+	}, nil
+}
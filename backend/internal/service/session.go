@@ -0,0 +1,321 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// TokenPair 登录成功后返回的令牌
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // 访问令牌有效期（秒）
+}
+
+// SessionClient 发起登录的客户端信息
+type SessionClient struct {
+	UserAgent string
+	IP        string
+}
+
+// IssueTokens 为用户创建新的登录会话，并签发访问令牌和刷新令牌
+func IssueTokens(userID int, client SessionClient) (*TokenPair, error) {
+	db := database.GetDB()
+
+	// 1. 生成会话ID
+	sessionID, err := utils.GenerateRandomToken(24)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 创建会话和第一个刷新令牌
+	var refreshToken string
+	err = db.Transaction(func(tx *gorm.DB) error {
+		userAgent := truncateUserAgent(client.UserAgent)
+		now := time.Now()
+		session := models.Session{
+			ID:         sessionID,
+			UserID:     userID,
+			UserAgent:  userAgent,
+			IP:         client.IP,
+			LastSeenAt: now,
+			ExpiresAt:  now.AddDate(0, 0, constants.RefreshTokenExpiryDays),
+		}
+		if err := tx.Create(&session).Error; err != nil {
+			return err
+		}
+
+		var err error
+		refreshToken, err = createRefreshToken(tx, &session)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 签发访问令牌
+	return buildTokenPair(userID, sessionID, refreshToken)
+}
+
+// RefreshTokens 使用刷新令牌换取新的访问令牌，同时轮换刷新令牌
+// 已经使用过的刷新令牌再次出现说明令牌可能泄露，此时注销整个会话
+func RefreshTokens(refreshToken string) (*TokenPair, error) {
+	db := database.GetDB()
+
+	// 1. 查找刷新令牌
+	var token models.RefreshToken
+	if err := db.Where("token_hash = ?", utils.HashToken(refreshToken)).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("invalid refresh token")
+		}
+		return nil, err
+	}
+
+	// 2. 检查会话是否有效
+	var session models.Session
+	if err := db.First(&session, "id = ?", token.SessionID).Error; err != nil {
+		return nil, err
+	}
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	// 3. 令牌重复使用：注销会话（先于封禁检查，被封禁用户的令牌泄露时也要注销会话）
+	if token.UsedAt != nil {
+		if err := RevokeSession(session.UserID, session.ID); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("refresh token reuse detected")
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	// 4. 被封禁的用户不能刷新令牌
+	user, err := GetUserByID(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSuspended() {
+		return nil, fmt.Errorf("account suspended")
+	}
+
+	// 5. 标记旧令牌已使用并生成新令牌
+	var newRefreshToken string
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// 带条件更新，避免并发请求同时使用同一个令牌
+		result := tx.Model(&token).Where("used_at IS NULL").Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("refresh token reuse detected")
+		}
+
+		if err := tx.Model(&session).Update("last_seen_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		var err error
+		newRefreshToken, err = createRefreshToken(tx, &session)
+		return err
+	})
+	if err != nil {
+		if err.Error() == "refresh token reuse detected" {
+			if revokeErr := RevokeSession(session.UserID, session.ID); revokeErr != nil {
+				return nil, revokeErr
+			}
+		}
+		return nil, err
+	}
+
+	// 6. 签发新的访问令牌
+	return buildTokenPair(session.UserID, session.ID, newRefreshToken)
+}
+
+// ValidateSession 判断会话是否有效（未注销且未过期），有效时更新最近活动时间
+// 最近活动时间每隔 SessionLastSeenUpdateMinutes 才写一次数据库，避免每个请求都写入
+func ValidateSession(sessionID string) (bool, error) {
+	db := database.GetDB()
+
+	// 1. 查询会话
+	var session models.Session
+	err := db.Where("id = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, time.Now()).
+		First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// 2. 按间隔更新最近活动时间
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) >= constants.SessionLastSeenUpdateMinutes*time.Minute {
+		if err := db.Model(&session).Update("last_seen_at", now).Error; err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// GetActiveSessions 获取用户所有有效的会话（最近活动的在前），标记出当前会话
+func GetActiveSessions(userID int, currentSessionID string) ([]models.Session, error) {
+	db := database.GetDB()
+
+	sessions := []models.Session{}
+	if err := db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeActiveSession 注销用户的某个有效会话（会话不存在或已注销时返回 record not found）
+func RevokeActiveSession(userID int, sessionID string) error {
+	db := database.GetDB()
+
+	result := db.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, userID, time.Now()).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RevokeSession 注销用户的某个会话
+func RevokeSession(userID int, sessionID string) error {
+	db := database.GetDB()
+
+	result := db.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+	return result.Error
+}
+
+// RevokeAllSessions 注销用户的所有会话，并撤销所有个人访问令牌
+func RevokeAllSessions(userID int) error {
+	return database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := revokeSessionsExcept(tx, userID, ""); err != nil {
+			return err
+		}
+		return revokePersonalAccessTokens(tx, userID)
+	})
+}
+
+// revokeSessionsExcept 注销用户除 keepSessionID 以外的所有会话（keepSessionID 为空时全部注销）
+func revokeSessionsExcept(tx *gorm.DB, userID int, keepSessionID string) error {
+	query := tx.Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if keepSessionID != "" {
+		query = query.Where("id != ?", keepSessionID)
+	}
+	return query.Update("revoked_at", time.Now()).Error
+}
+
+// createRefreshToken 在会话中生成一个新的刷新令牌，返回令牌明文
+func createRefreshToken(tx *gorm.DB, session *models.Session) (string, error) {
+	plain, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	token := models.RefreshToken{
+		SessionID: session.ID,
+		TokenHash: utils.HashToken(plain),
+		ExpiresAt: session.ExpiresAt,
+	}
+	if err := tx.Create(&token).Error; err != nil {
+		return "", err
+	}
+	return plain, nil
+}
+
+// buildTokenPair 签发访问令牌并组装返回结果（访问令牌中带上用户当前的角色）
+func buildTokenPair(userID int, sessionID string, refreshToken string) (*TokenPair, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := utils.GenerateToken(userID, sessionID, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    constants.AccessTokenExpiryMinutes * 60,
+	}, nil
+}
+
+// verifyReauthentication 敏感操作前确认是本人操作（需在数据库事务中调用）
+// 提供了密码时验证密码；没有密码时，绑定了第三方登录的用户（可能从未设置过密码）
+// 需要当前会话是最近刚登录的，否则请用户重新登录后再操作
+func verifyReauthentication(tx *gorm.DB, user *models.User, sessionID string, password string) error {
+	// 1. 验证密码
+	if password != "" {
+		if err := utils.CheckPassword(user.PasswordHash, password); err != nil {
+			return fmt.Errorf("current password is incorrect")
+		}
+		return nil
+	}
+
+	// 2. 只有绑定了第三方登录的用户可以不输入密码
+	var identities int64
+	if err := tx.Model(&models.UserIdentity{}).Where("user_id = ?", user.ID).Count(&identities).Error; err != nil {
+		return err
+	}
+	if identities == 0 {
+		return fmt.Errorf("current password is incorrect")
+	}
+
+	// 3. 当前会话必须是最近登录的（个人访问令牌没有会话，不能用于该操作）
+	if sessionID == "" {
+		return fmt.Errorf("recent login required")
+	}
+	var session models.Session
+	err := tx.Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, user.ID).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("recent login required")
+		}
+		return err
+	}
+	if time.Since(session.CreatedAt) > constants.ReauthMaxAgeMinutes*time.Minute {
+		return fmt.Errorf("recent login required")
+	}
+	return nil
+}
+
+// truncateUserAgent 截断过长的 User-Agent（不超过 MaxUserAgentLength 字节），只在字符边界截断
+// 同时替换非法的 UTF-8 字节，否则 PostgreSQL 会拒绝写入导致无法登录
+func truncateUserAgent(userAgent string) string {
+	userAgent = strings.ToValidUTF8(userAgent, "\uFFFD")
+	if len(userAgent) <= constants.MaxUserAgentLength {
+		return userAgent
+	}
+	end := constants.MaxUserAgentLength
+	for end > 0 && !utf8.RuneStart(userAgent[end]) {
+		end--
+	}
+	return userAgent[:end]
+}
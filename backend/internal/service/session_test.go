@@ -0,0 +1,38 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"backend/internal/constants"
+)
+
+func TestTruncateUserAgent(t *testing.T) {
+	max := constants.MaxUserAgentLength
+
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{name: "short", userAgent: "Mozilla/5.0", want: "Mozilla/5.0"},
+		{name: "exact length", userAgent: strings.Repeat("a", max), want: strings.Repeat("a", max)},
+		{name: "ascii too long", userAgent: strings.Repeat("a", max+10), want: strings.Repeat("a", max)},
+		// 3 字节的汉字跨过长度上限，整个字符去掉
+		{name: "multi-byte at boundary", userAgent: strings.Repeat("a", max-1) + "浏览器", want: strings.Repeat("a", max-1)},
+		{name: "multi-byte only", userAgent: strings.Repeat("浏", max), want: strings.Repeat("浏", max/3)},
+		{name: "invalid utf-8", userAgent: "agent\xff", want: "agent�"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateUserAgent(tt.userAgent)
+			if got != tt.want {
+				t.Fatalf("truncateUserAgent = %q, want %q", got, tt.want)
+			}
+			if len(got) > max || !utf8.ValidString(got) {
+				t.Fatalf("result must be valid UTF-8 within %d bytes, got %d bytes", max, len(got))
+			}
+		})
+	}
+}
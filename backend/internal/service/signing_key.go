@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"log"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// signingKeyLockID 生成和轮换签名密钥时使用的数据库 advisory lock，多个实例同时启动时只有一个实例生成密钥
+const signingKeyLockID = 7301
+
+// LoadSigningKeys 加载 JWT 签名密钥：删除已过期的密钥，当前密钥超过轮换周期（或无法解密）时生成新密钥
+// 旧密钥在 SigningKeyGraceMinutes 内仍用于验证，保证轮换前签发的令牌在过期前可用
+func LoadSigningKeys() error {
+	db := database.GetDB()
+	secret := config.AppConfig.JWTSecret
+
+	var current utils.SigningKey
+	var verificationKeys []utils.SigningKey
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", signingKeyLockID).Error; err != nil {
+			return err
+		}
+
+		// 1. 删除已过期的密钥
+		now := time.Now()
+		if err := tx.Where("expires_at IS NOT NULL AND expires_at < ?", now).
+			Delete(&models.SigningKey{}).Error; err != nil {
+			return err
+		}
+
+		// 2. 查找仍在签名期内且可以解密的最新密钥
+		var active []models.SigningKey
+		if err := tx.Where("expires_at IS NULL").Order("created_at DESC").Find(&active).Error; err != nil {
+			return err
+		}
+		rotateBefore := now.AddDate(0, 0, -constants.SigningKeyRotationDays)
+		found := false
+		for _, key := range active {
+			if key.CreatedAt.Before(rotateBefore) {
+				break
+			}
+			privateKey, err := utils.DecryptSecret(secret, key.EncryptedPrivateKey)
+			if err != nil || len(privateKey) != ed25519.PrivateKeySize {
+				log.Printf("⚠️  Signing key %s cannot be decrypted, rotating", key.Kid)
+				break
+			}
+			current = utils.SigningKey{
+				ID:         key.Kid,
+				PrivateKey: ed25519.PrivateKey(privateKey),
+				PublicKey:  ed25519.PublicKey(key.PublicKey),
+			}
+			found = true
+			break
+		}
+
+		// 3. 需要轮换：生成新密钥，其他密钥停止签名，宽限期后过期
+		if !found {
+			key, err := createSigningKey(tx, secret)
+			if err != nil {
+				return err
+			}
+			current = *key
+			if err := tx.Model(&models.SigningKey{}).
+				Where("expires_at IS NULL AND kid != ?", key.ID).
+				Update("expires_at", now.Add(constants.SigningKeyGraceMinutes*time.Minute)).Error; err != nil {
+				return err
+			}
+			log.Printf("🔑 Generated new signing key %s", key.ID)
+		}
+
+		// 4. 所有未过期的密钥都可用于验证
+		var keys []models.SigningKey
+		if err := tx.Where("expires_at IS NULL OR expires_at >= ?", now).Find(&keys).Error; err != nil {
+			return err
+		}
+		for _, key := range keys {
+			verificationKeys = append(verificationKeys, utils.SigningKey{
+				ID:        key.Kid,
+				PublicKey: ed25519.PublicKey(key.PublicKey),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	utils.SetSigningKeys(current, verificationKeys)
+	return nil
+}
+
+// StartSigningKeyRefresher 定期重新加载签名密钥（轮换以及获取其他实例生成的密钥），直到 ctx 被取消
+// 验证令牌时遇到未知的 kid 也会立即重新加载（有最小间隔限制）
+func StartSigningKeyRefresher(ctx context.Context) {
+	utils.SetSigningKeyReloader(LoadSigningKeys)
+
+	ticker := time.NewTicker(constants.SigningKeyRefreshMinutes * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := LoadSigningKeys(); err != nil {
+				log.Printf("⚠️  Failed to refresh signing keys: %v", err)
+			}
+		}
+	}
+}
+
+// createSigningKey 生成新的 Ed25519 密钥并加密保存
+func createSigningKey(tx *gorm.DB, secret string) (*utils.SigningKey, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	kid, err := utils.GenerateRandomToken(12)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := utils.EncryptSecret(secret, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key := models.SigningKey{
+		Kid:                 kid,
+		Algorithm:           "EdDSA",
+		PublicKey:           publicKey,
+		EncryptedPrivateKey: encrypted,
+	}
+	if err := tx.Create(&key).Error; err != nil {
+		return nil, err
+	}
+
+	return &utils.SigningKey{ID: kid, PrivateKey: privateKey, PublicKey: publicKey}, nil
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+)
+
+// StatsRange 统计的日期范围（按 UTC 日期，包含开始和结束当天）
+type StatsRange struct {
+	From time.Time // 开始日期
+	To   time.Time // 结束日期
+}
+
+// NewStatsRange 校验并规范化日期范围：未指定结束日期时为今天，未指定开始日期时向前推默认天数
+func NewStatsRange(from, to *time.Time) (StatsRange, error) {
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	if to != nil {
+		end = to.UTC().Truncate(24 * time.Hour)
+	}
+	start := end.AddDate(0, 0, -(constants.StatsDefaultRangeDays - 1))
+	if from != nil {
+		start = from.UTC().Truncate(24 * time.Hour)
+	}
+
+	if start.After(end) {
+		return StatsRange{}, fmt.Errorf("invalid date range")
+	}
+	if end.Sub(start) >= constants.StatsMaxRangeDays*24*time.Hour {
+		return StatsRange{}, fmt.Errorf("date range too long")
+	}
+	return StatsRange{From: start, To: end}, nil
+}
+
+// end 结束日期的下一天 0 点（查询条件使用 created_at < end）
+func (r StatsRange) end() time.Time {
+	return r.To.AddDate(0, 0, 1)
+}
+
+// key 缓存键
+func (r StatsRange) key(metric string) string {
+	return metric + ":" + r.From.Format("2006-01-02") + ":" + r.To.Format("2006-01-02")
+}
+
+// days 范围内的每一天（用于补齐没有数据的日期）
+func (r StatsRange) days() []string {
+	var days []string
+	for d := r.From; !d.After(r.To); d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format("2006-01-02"))
+	}
+	return days
+}
+
+// statsCacheEntry 缓存的统计结果
+type statsCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+var (
+	statsCacheMu sync.Mutex
+	statsCache   = map[string]statsCacheEntry{}
+)
+
+// cachedStats 返回缓存的统计结果，过期或不存在时重新计算
+// 统计查询需要扫描整张表，缓存避免仪表盘刷新时反复计算
+func cachedStats(key string, compute func() (interface{}, error)) (interface{}, error) {
+	statsCacheMu.Lock()
+	entry, ok := statsCache[key]
+	statsCacheMu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+
+	value, err := compute()
+	if err != nil {
+		return nil, err
+	}
+
+	statsCacheMu.Lock()
+	defer statsCacheMu.Unlock()
+	now := time.Now()
+	for k, e := range statsCache { // 顺便清理过期的结果，避免不同日期范围的缓存无限增长
+		if now.After(e.expiresAt) {
+			delete(statsCache, k)
+		}
+	}
+	statsCache[key] = statsCacheEntry{value: value, expiresAt: now.Add(constants.StatsCacheTTLSeconds * time.Second)}
+	return value, nil
+}
+
+// dailyCount 按日期统计的数量
+type dailyCount struct {
+	Day   time.Time
+	Count int64
+}
+
+// countByDay 执行按日期分组的计数查询，返回 日期 -> 数量
+func countByDay(query string, values ...interface{}) (map[string]int64, error) {
+	db := database.GetDB()
+
+	var rows []dailyCount
+	if err := db.Raw(query, values...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Day.Format("2006-01-02")] = row.Count
+	}
+	return counts, nil
+}
+
+// DailyUserStats 每天的新注册用户数
+type DailyUserStats struct {
+	Date     string `json:"date"`
+	NewUsers int64  `json:"new_users"`
+}
+
+// GetUserStats 统计日期范围内每天的新注册用户数（需要查看统计的权限）
+func GetUserStats(actor Actor, r StatsRange) ([]DailyUserStats, error) {
+	// 1. 验证权限
+	if !actor.Can(constants.PermissionViewStats) {
+		return nil, fmt.Errorf("unauthorized: you cannot view stats")
+	}
+
+	// 2. 查询（结果缓存）
+	value, err := cachedStats(r.key("users"), func() (interface{}, error) {
+		counts, err := countByDay(`SELECT date_trunc('day', created_at AT TIME ZONE 'UTC') AS day, COUNT(*) AS count
+			FROM users WHERE created_at >= ? AND created_at < ? GROUP BY day`, r.From, r.end())
+		if err != nil {
+			return nil, err
+		}
+
+		stats := []DailyUserStats{}
+		for _, day := range r.days() {
+			stats = append(stats, DailyUserStats{Date: day, NewUsers: counts[day]})
+		}
+		return stats, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]DailyUserStats), nil
+}
+
+// DailyPostStats 每天发布、售出、删除的商品数
+type DailyPostStats struct {
+	Date    string `json:"date"`
+	Created int64  `json:"created"`
+	Sold    int64  `json:"sold"`
+	Deleted int64  `json:"deleted"`
+}
+
+// GetPostStats 统计日期范围内每天发布、售出、删除的商品数（需要查看统计的权限）
+// 售出时间使用 sold_at（早于该字段的已售商品使用 updated_at）；删除时间使用已删除商品的 updated_at
+func GetPostStats(actor Actor, r StatsRange) ([]DailyPostStats, error) {
+	// 1. 验证权限
+	if !actor.Can(constants.PermissionViewStats) {
+		return nil, fmt.Errorf("unauthorized: you cannot view stats")
+	}
+
+	// 2. 查询（结果缓存）
+	value, err := cachedStats(r.key("posts"), func() (interface{}, error) {
+		created, err := countByDay(`SELECT date_trunc('day', created_at AT TIME ZONE 'UTC') AS day, COUNT(*) AS count
+			FROM posts WHERE created_at >= ? AND created_at < ? GROUP BY day`, r.From, r.end())
+		if err != nil {
+			return nil, err
+		}
+		sold, err := countByDay(`SELECT date_trunc('day', sold_time AT TIME ZONE 'UTC') AS day, COUNT(*) AS count
+			FROM (SELECT COALESCE(sold_at, updated_at) AS sold_time FROM posts
+				WHERE sold_at IS NOT NULL OR status = ?) sold
+			WHERE sold_time >= ? AND sold_time < ? GROUP BY day`, constants.PostStatusSold, r.From, r.end())
+		if err != nil {
+			return nil, err
+		}
+		deleted, err := countByDay(`SELECT date_trunc('day', updated_at AT TIME ZONE 'UTC') AS day, COUNT(*) AS count
+			FROM posts WHERE status = ? AND updated_at >= ? AND updated_at < ? GROUP BY day`,
+			constants.PostStatusDeleted, r.From, r.end())
+		if err != nil {
+			return nil, err
+		}
+
+		stats := []DailyPostStats{}
+		for _, day := range r.days() {
+			stats = append(stats, DailyPostStats{Date: day, Created: created[day], Sold: sold[day], Deleted: deleted[day]})
+		}
+		return stats, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]DailyPostStats), nil
+}
+
+// CategoryPriceStats 单个分类在日期范围内的商品数和中位价格
+type CategoryPriceStats struct {
+	Category           string   `json:"category"`
+	ListedCount        int64    `json:"listed_count"`         // 日期范围内发布的商品数
+	MedianListingPrice *float64 `json:"median_listing_price"` // 日期范围内发布的商品的中位价格，没有数据时为 null
+	SoldCount          int64    `json:"sold_count"`           // 日期范围内售出的商品数
+	MedianSoldPrice    *float64 `json:"median_sold_price"`    // 日期范围内售出的商品的中位价格，没有数据时为 null
+}
+
+// MarketplaceSummary 市场概况（中位价格同时给出全部商品和各分类的结果）
+type MarketplaceSummary struct {
+	From                  string   `json:"from"`
+	To                    string   `json:"to"`
+	ActiveListings        int64    `json:"active_listings"`           // 当前在售商品数（不受日期范围影响）
+	SoldCount             int64    `json:"sold_count"`                // 日期范围内售出的商品数
+	MedianTimeToSellHours *float64 `json:"median_time_to_sell_hours"` // 日期范围内售出的商品从发布到售出的中位用时，没有数据时为 null
+	MedianListingPrice    *float64 `json:"median_listing_price"`      // 日期范围内发布的商品的中位价格
+	MedianSoldPrice       *float64 `json:"median_sold_price"`         // 日期范围内售出的商品的中位价格
+
+	Categories []CategoryPriceStats `json:"categories"` // 各分类的中位价格（包含没有数据的分类）
+}
+
+// GetMarketplaceSummary 统计在售商品数、售出用时和价格中位数（需要查看统计的权限）
+func GetMarketplaceSummary(actor Actor, r StatsRange) (*MarketplaceSummary, error) {
+	db := database.GetDB()
+
+	// 1. 验证权限
+	if !actor.Can(constants.PermissionViewStats) {
+		return nil, fmt.Errorf("unauthorized: you cannot view stats")
+	}
+
+	// 2. 查询（结果缓存）
+	value, err := cachedStats(r.key("summary"), func() (interface{}, error) {
+		summary := &MarketplaceSummary{From: r.From.Format("2006-01-02"), To: r.To.Format("2006-01-02")}
+
+		if err := db.Raw(`SELECT COUNT(*) FROM posts WHERE status = ?`, constants.PostStatusActive).
+			Scan(&summary.ActiveListings).Error; err != nil {
+			return nil, err
+		}
+
+		var sold struct {
+			Count               int64
+			MedianSecondsToSell *float64
+			MedianPrice         *float64
+		}
+		if err := db.Raw(`SELECT COUNT(*) AS count,
+				percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM sold_time - created_at)) AS median_seconds_to_sell,
+				percentile_cont(0.5) WITHIN GROUP (ORDER BY price) AS median_price
+			FROM (SELECT COALESCE(sold_at, updated_at) AS sold_time, created_at, price FROM posts
+				WHERE sold_at IS NOT NULL OR status = ?) sold
+			WHERE sold_time >= ? AND sold_time < ?`, constants.PostStatusSold, r.From, r.end()).
+			Scan(&sold).Error; err != nil {
+			return nil, err
+		}
+		summary.SoldCount = sold.Count
+		summary.MedianSoldPrice = sold.MedianPrice
+		if sold.MedianSecondsToSell != nil {
+			hours := *sold.MedianSecondsToSell / 3600
+			summary.MedianTimeToSellHours = &hours
+		}
+
+		var listed struct {
+			MedianPrice *float64
+		}
+		if err := db.Raw(`SELECT percentile_cont(0.5) WITHIN GROUP (ORDER BY price) AS median_price
+			FROM posts WHERE created_at >= ? AND created_at < ?`, r.From, r.end()).
+			Scan(&listed).Error; err != nil {
+			return nil, err
+		}
+		summary.MedianListingPrice = listed.MedianPrice
+
+		categories, err := categoryPriceStats(r)
+		if err != nil {
+			return nil, err
+		}
+		summary.Categories = categories
+		return summary, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*MarketplaceSummary), nil
+}
+
+// categoryPriceStats 按分类统计日期范围内发布和售出的商品数及中位价格
+// 售出时间的判断与 GetMarketplaceSummary 一致（没有 sold_at 的旧数据使用 updated_at）
+func categoryPriceStats(r StatsRange) ([]CategoryPriceStats, error) {
+	db := database.GetDB()
+
+	var rows []CategoryPriceStats
+	if err := db.Raw(`SELECT category,
+			COUNT(*) FILTER (WHERE created_at >= @from AND created_at < @to) AS listed_count,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY price) FILTER (WHERE created_at >= @from AND created_at < @to) AS median_listing_price,
+			COUNT(*) FILTER (WHERE sold_time >= @from AND sold_time < @to) AS sold_count,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY price) FILTER (WHERE sold_time >= @from AND sold_time < @to) AS median_sold_price
+		FROM (SELECT category, price, created_at,
+				CASE WHEN sold_at IS NOT NULL OR status = @sold THEN COALESCE(sold_at, updated_at) END AS sold_time
+			FROM posts) p
+		GROUP BY category`,
+		sql.Named("from", r.From), sql.Named("to", r.end()), sql.Named("sold", constants.PostStatusSold)).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	// 按固定顺序返回所有分类，没有数据的分类计数为 0、中位价格为 null
+	byCategory := make(map[string]CategoryPriceStats, len(rows))
+	for _, row := range rows {
+		byCategory[row.Category] = row
+	}
+	stats := make([]CategoryPriceStats, 0, len(postCategories))
+	for _, category := range postCategories {
+		row, ok := byCategory[category]
+		if !ok {
+			row = CategoryPriceStats{Category: category}
+		}
+		stats = append(stats, row)
+	}
+	return stats, nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SuspendUser 封禁用户（until 为 nil 表示永久封禁）
+func SuspendUser(actor Actor, userID int, reason string, until *time.Time) (*models.User, error) {
+	db := database.GetDB()
+
+	// 1. 验证权限和参数
+	if !actor.Can(constants.PermissionManageUsers) {
+		return nil, fmt.Errorf("unauthorized: you cannot manage users")
+	}
+	if actor.UserID == userID {
+		return nil, fmt.Errorf("you cannot suspend yourself")
+	}
+	if until != nil && !until.After(time.Now()) {
+		return nil, fmt.Errorf("suspension end must be in the future")
+	}
+
+	// 2. 封禁并隐藏在售商品
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return suspendUser(tx, actor, userID, reason, until)
+	}); err != nil {
+		return nil, err
+	}
+
+	return GetUserByID(userID)
+}
+
+// LiftSuspension 解除封禁，并恢复因封禁而隐藏的商品
+func LiftSuspension(actor Actor, userID int) (*models.User, error) {
+	db := database.GetDB()
+
+	// 1. 验证权限
+	if !actor.Can(constants.PermissionManageUsers) {
+		return nil, fmt.Errorf("unauthorized: you cannot manage users")
+	}
+
+	// 2. 解除封禁
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+		if user.SuspendedAt == nil {
+			return fmt.Errorf("user is not suspended")
+		}
+		return liftSuspension(tx, actor, &user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return GetUserByID(userID)
+}
+
+// SuspensionMessage 返回给被封禁用户的错误提示（包含封禁原因和结束时间）
+func SuspensionMessage(user *models.User) string {
+	msg := constants.ErrAccountSuspended
+	if user.SuspendedUntil != nil {
+		msg += " until " + user.SuspendedUntil.UTC().Format("2006-01-02 15:04 MST")
+	}
+	if user.SuspensionReason != "" {
+		msg += ": " + user.SuspensionReason
+	}
+	return msg
+}
+
+// StartSuspensionWorker 定期解除到期的临时封禁，直到 ctx 结束
+func StartSuspensionWorker(ctx context.Context) {
+	ticker := time.NewTicker(constants.SuspensionWorkerIntervalMinutes * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := liftExpiredSuspensions(); err != nil {
+				log.Printf("⚠️  Suspension worker error: %v", err)
+			}
+		}
+	}
+}
+
+// liftExpiredSuspensions 解除所有已到期的临时封禁
+// 使用 FOR UPDATE SKIP LOCKED，多个实例同时运行时不会重复处理
+func liftExpiredSuspensions() error {
+	db := database.GetDB()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var users []models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("suspended_at IS NOT NULL AND suspended_until <= ?", time.Now()).
+			Find(&users).Error; err != nil {
+			return err
+		}
+		for i := range users {
+			if err := liftSuspension(tx, Actor{}, &users[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// suspendUser 封禁用户并隐藏其所有在售商品，并记录审计日志（需在数据库事务中调用）
+// 会话不会被注销，封禁期间 AuthMiddleware 会拒绝该用户的请求
+func suspendUser(tx *gorm.DB, actor Actor, userID int, reason string, until *time.Time) error {
+	// 1. 查询用户并加锁（不能封禁管理员）
+	var user models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+		return err
+	}
+	if user.Role == constants.RoleAdmin {
+		return fmt.Errorf("cannot suspend an admin")
+	}
+
+	// 2. 记录封禁（再次封禁时覆盖原来的原因和结束时间）
+	before := suspensionState(&user)
+	if err := tx.Model(&user).Updates(map[string]interface{}{
+		"suspended_at":      time.Now(),
+		"suspended_until":   until,
+		"suspension_reason": reason,
+	}).Error; err != nil {
+		return err
+	}
+
+	// 3. 隐藏在售商品
+	result := tx.Model(&models.Post{}).
+		Where("user_id = ? AND status = ?", user.ID, constants.PostStatusActive).
+		Updates(map[string]interface{}{
+			"status":        constants.PostStatusHidden,
+			"hidden_reason": constants.HiddenReasonSuspension,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// 4. 记录审计日志
+	after := suspensionState(&user)
+	after["hidden_posts"] = result.RowsAffected
+	return recordAudit(tx, actor, constants.AuditActionUserSuspend, constants.AuditTargetUser, user.ID, before, after)
+}
+
+// liftSuspension 清除封禁记录，恢复因封禁而隐藏的商品，并记录审计日志（需在数据库事务中调用）
+// 被管理员单独隐藏的商品保持隐藏；到期自动解封时 actor 为空
+func liftSuspension(tx *gorm.DB, actor Actor, user *models.User) error {
+	before := suspensionState(user)
+	if err := tx.Model(user).Updates(map[string]interface{}{
+		"suspended_at":      nil,
+		"suspended_until":   nil,
+		"suspension_reason": "",
+	}).Error; err != nil {
+		return err
+	}
+
+	result := tx.Model(&models.Post{}).
+		Where("user_id = ? AND status = ? AND hidden_reason = ?",
+			user.ID, constants.PostStatusHidden, constants.HiddenReasonSuspension).
+		Updates(map[string]interface{}{
+			"status":        constants.PostStatusActive,
+			"hidden_reason": "",
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	after := suspensionState(user)
+	after["restored_posts"] = result.RowsAffected
+	return recordAudit(tx, actor, constants.AuditActionUserUnsuspend, constants.AuditTargetUser, user.ID, before, after)
+}
+
+// suspensionState 用户的封禁字段（用于审计日志）
+func suspensionState(user *models.User) models.AuditState {
+	return models.AuditState{
+		"suspended_at":      user.SuspendedAt,
+		"suspended_until":   user.SuspendedUntil,
+		"suspension_reason": user.SuspensionReason,
+	}
+}
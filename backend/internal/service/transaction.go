@@ -0,0 +1,141 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// createTransaction 为已售出的商品生成交易记录（需在数据库事务中调用）
+func createTransaction(tx *gorm.DB, post *models.Post, buyerUsername string) error {
+	// 1. 查找买家
+	var buyer models.User
+	if err := tx.Where("username = ?", buyerUsername).First(&buyer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("buyer not found")
+		}
+		return err
+	}
+
+	// 2. 卖家不能是买家
+	if buyer.ID == post.UserID {
+		return fmt.Errorf("buyer cannot be the seller")
+	}
+
+	// 3. 买家必须查看过该商品的联系方式（与卖家有过联系），不能把商品记到任意用户名下
+	var reveals int64
+	if err := tx.Model(&models.ContactReveal{}).
+		Where("post_id = ? AND user_id = ?", post.ID, buyer.ID).
+		Count(&reveals).Error; err != nil {
+		return err
+	}
+	if reveals == 0 {
+		return fmt.Errorf("buyer has not contacted the seller")
+	}
+
+	// 4. 屏蔽了卖家的用户不能被记录为买家（否则会收到通知并可以评价）
+	var blocks int64
+	if err := tx.Model(&models.UserBlock{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)",
+			buyer.ID, post.UserID, post.UserID, buyer.ID).
+		Count(&blocks).Error; err != nil {
+		return err
+	}
+	if blocks > 0 {
+		return fmt.Errorf("buyer is blocked")
+	}
+
+	// 5. 每个商品只能有一笔交易
+	var count int64
+	if err := tx.Model(&models.Transaction{}).Where("post_id = ?", post.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("sale already recorded for this post")
+	}
+
+	// 6. 保存交易记录（价格以成交时为准）
+	transaction := models.Transaction{
+		PostID:   post.ID,
+		SellerID: post.UserID,
+		BuyerID:  buyer.ID,
+		Price:    post.Price,
+	}
+	if err := tx.Create(&transaction).Error; err != nil {
+		return err
+	}
+
+	// 7. 通知买家交易已记录，可以评价卖家
+	postID := post.ID
+	return Notify(tx, NotificationEvent{
+		UserID: buyer.ID,
+		Type:   constants.NotificationTypeItemSold,
+		Title:  "Your purchase has been recorded",
+		Body:   fmt.Sprintf("The seller marked %s as sold to you. You can now leave a review.", post.Title),
+		PostID: &postID,
+	})
+}
+
+// GetMyTransactionsRequest 获取我的交易列表请求参数
+type GetMyTransactionsRequest struct {
+	UserID   int // 用户ID
+	Page     int // 页码，从1开始
+	PageSize int // 每页数量
+}
+
+// GetMyTransactionsResponse 获取我的交易列表响应
+type GetMyTransactionsResponse struct {
+	Transactions []models.Transaction `json:"transactions"`
+	TotalCount   int64                `json:"total_count"` // 总数量
+	Page         int                  `json:"page"`        // 当前页码
+	PageSize     int                  `json:"page_size"`   // 每页数量
+	TotalPages   int                  `json:"total_pages"` // 总页数
+}
+
+// GetMyTransactions 获取我参与的交易（作为买家或卖家）
+func GetMyTransactions(req GetMyTransactionsRequest) (*GetMyTransactionsResponse, error) {
+	db := database.GetDB()
+
+	// 1. 设置默认值
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	// 2. 计算偏移量
+	offset := (req.Page - 1) * req.PageSize
+
+	// 3. 查询总数量
+	var totalCount int64
+	if err := db.Model(&models.Transaction{}).
+		Where("buyer_id = ? OR seller_id = ?", req.UserID, req.UserID).
+		Count(&totalCount).Error; err != nil {
+		return nil, err
+	}
+
+	// 4. 查询分页数据（包含商品和双方用户信息）
+	var transactions []models.Transaction
+	if err := db.Preload("Post").Preload("Seller").Preload("Buyer").
+		Where("buyer_id = ? OR seller_id = ?", req.UserID, req.UserID).
+		Order("created_at DESC").
+		Limit(req.PageSize).
+		Offset(offset).
+		Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	return &GetMyTransactionsResponse{
+		Transactions: transactions,
+		TotalCount:   totalCount,
+		Page:         req.Page,
+		PageSize:     req.PageSize,
+		TotalPages:   calcTotalPages(totalCount, req.PageSize),
+	}, nil
+}
@@ -0,0 +1,338 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// TwoFactorEnrollment 两步验证注册信息
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`      // 手动输入用的密钥
+	OTPAuthURI string `json:"otpauth_uri"` // 用于生成二维码
+}
+
+// StartTwoFactorEnrollment 开始注册两步验证：生成新的 TOTP 密钥（需调用 ConfirmTwoFactor 确认后才生效）
+func StartTwoFactorEnrollment(userID int) (*TwoFactorEnrollment, error) {
+	db := database.GetDB()
+
+	// 1. 查询用户
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, fmt.Errorf("two-factor authentication already enabled")
+	}
+
+	// 2. 生成并保存待确认的密钥（加密保存）
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := encryptTOTPSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Model(&user).Updates(map[string]interface{}{
+		"totp_secret":    encrypted,
+		"totp_last_step": 0,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(constants.TOTPIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor 使用验证器生成的第一个验证码确认注册，启用两步验证并返回备用码
+func ConfirmTwoFactor(userID int, code string) ([]string, error) {
+	db := database.GetDB()
+
+	var backupCodes []string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 1. 查询用户
+		var user models.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return err
+		}
+		if user.TwoFactorEnabled {
+			return fmt.Errorf("two-factor authentication already enabled")
+		}
+		if user.TOTPSecret == "" {
+			return fmt.Errorf("two-factor enrollment not started")
+		}
+
+		// 2. 验证第一个验证码
+		if err := verifyTOTP(tx, &user, code); err != nil {
+			return err
+		}
+
+		// 3. 启用两步验证并生成备用码
+		if err := tx.Model(&user).Update("two_factor_enabled", true).Error; err != nil {
+			return err
+		}
+		var err error
+		backupCodes, err = generateBackupCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return backupCodes, nil
+}
+
+// DisableTwoFactor 关闭两步验证（需要密码和有效的验证码或备用码）
+func DisableTwoFactor(userID int, password string, code string) error {
+	db := database.GetDB()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 查询用户
+		var user models.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return err
+		}
+		if !user.TwoFactorEnabled {
+			return fmt.Errorf("two-factor authentication not enabled")
+		}
+
+		// 2. 验证密码和验证码
+		if err := utils.CheckPassword(user.PasswordHash, password); err != nil {
+			return fmt.Errorf("current password is incorrect")
+		}
+		if err := verifyTwoFactorCode(tx, &user, code); err != nil {
+			return err
+		}
+
+		// 3. 清除密钥和备用码
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"two_factor_enabled": false,
+			"totp_secret":        "",
+			"totp_last_step":     0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", user.ID).Delete(&models.BackupCode{}).Error
+	})
+}
+
+// RegenerateBackupCodes 重新生成备用码（之前的备用码全部作废）
+func RegenerateBackupCodes(userID int, code string) ([]string, error) {
+	db := database.GetDB()
+
+	var backupCodes []string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 1. 查询用户
+		var user models.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return err
+		}
+		if !user.TwoFactorEnabled {
+			return fmt.Errorf("two-factor authentication not enabled")
+		}
+
+		// 2. 需要验证器生成的验证码（不接受备用码）
+		if err := verifyTOTP(tx, &user, code); err != nil {
+			return err
+		}
+
+		var err error
+		backupCodes, err = generateBackupCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return backupCodes, nil
+}
+
+// VerifyTwoFactorLogin 登录第二步：验证验证码或备用码
+func VerifyTwoFactorLogin(userID int, code string) (*models.User, error) {
+	db := database.GetDB()
+
+	var user models.User
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&user, userID).Error; err != nil {
+			return err
+		}
+		if !user.TwoFactorEnabled {
+			return fmt.Errorf("two-factor authentication not enabled")
+		}
+		return verifyTwoFactorCode(tx, &user, code)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+var totpCodePattern = regexp.MustCompile(`^\d{6}$`)
+
+// verifyTwoFactorCode 验证 TOTP 验证码或备用码
+func verifyTwoFactorCode(tx *gorm.DB, user *models.User, code string) error {
+	code = strings.TrimSpace(code)
+	if totpCodePattern.MatchString(code) {
+		return verifyTOTP(tx, user, code)
+	}
+	return useBackupCode(tx, user.ID, code)
+}
+
+// verifyTOTP 验证 TOTP 验证码，每个时间步只能使用一次
+func verifyTOTP(tx *gorm.DB, user *models.User, code string) error {
+	secret, err := decryptTOTPSecret(user.TOTPSecret)
+	if err != nil {
+		return err
+	}
+	step, ok := utils.ValidateTOTP(secret, code, time.Now())
+	if !ok || step <= user.TOTPLastStep {
+		return fmt.Errorf("invalid two-factor code")
+	}
+
+	// 带条件更新，避免同一个验证码被并发使用；以前明文保存的密钥顺便加密
+	updates := map[string]interface{}{"totp_last_step": step}
+	if !strings.HasPrefix(user.TOTPSecret, totpSecretPrefix) {
+		encrypted, err := encryptTOTPSecret(secret)
+		if err != nil {
+			return err
+		}
+		updates["totp_secret"] = encrypted
+	}
+	result := tx.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", user.ID, step).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("invalid two-factor code")
+	}
+	user.TOTPLastStep = step
+	return nil
+}
+
+// useBackupCode 使用一个备用码
+func useBackupCode(tx *gorm.DB, userID int, code string) error {
+	hash := utils.HashToken(normalizeBackupCode(code))
+
+	var backupCode models.BackupCode
+	if err := tx.Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		First(&backupCode).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("invalid two-factor code")
+		}
+		return err
+	}
+
+	result := tx.Model(&backupCode).Where("used_at IS NULL").Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("invalid two-factor code")
+	}
+	return nil
+}
+
+// totpSecretPrefix 加密保存的 TOTP 密钥前缀（没有前缀的是加密之前保存的明文密钥）
+const totpSecretPrefix = "enc:"
+
+// encryptTOTPSecret 使用 JWT_SECRET 派生的密钥加密 TOTP 密钥，返回可保存到数据库的字符串
+func encryptTOTPSecret(secret string) (string, error) {
+	encrypted, err := utils.EncryptSecret(config.AppConfig.JWTSecret, []byte(secret))
+	if err != nil {
+		return "", err
+	}
+	return totpSecretPrefix + base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+// decryptTOTPSecret 解密数据库中保存的 TOTP 密钥（兼容以前的明文密钥）
+func decryptTOTPSecret(stored string) (string, error) {
+	if !strings.HasPrefix(stored, totpSecretPrefix) {
+		return stored, nil
+	}
+	encrypted, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, totpSecretPrefix))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode two-factor secret: %w", err)
+	}
+	secret, err := utils.DecryptSecret(config.AppConfig.JWTSecret, encrypted)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt two-factor secret: %w", err)
+	}
+	return string(secret), nil
+}
+
+// backupCodeAlphabet 备用码字符集（去掉容易混淆的 0/O、1/I/L）
+const backupCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// generateBackupCodes 生成新的备用码（删除旧的），返回明文，格式为 XXXXX-XXXXX
+func generateBackupCodes(tx *gorm.DB, userID int) ([]string, error) {
+	// 1. 删除旧的备用码
+	if err := tx.Where("user_id = ?", userID).Delete(&models.BackupCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	// 2. 生成新的备用码
+	codes := make([]string, 0, constants.BackupCodeCount)
+	records := make([]models.BackupCode, 0, constants.BackupCodeCount)
+	for i := 0; i < constants.BackupCodeCount; i++ {
+		chars, err := randomBackupCodeChars(10)
+		if err != nil {
+			return nil, err
+		}
+		code := string(chars[:5]) + "-" + string(chars[5:])
+
+		codes = append(codes, code)
+		records = append(records, models.BackupCode{
+			UserID:   userID,
+			CodeHash: utils.HashToken(normalizeBackupCode(code)),
+		})
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// normalizeBackupCode 统一备用码格式（忽略大小写、空格和连字符）
+func normalizeBackupCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+// randomBackupCodeChars 从备用码字符集中均匀随机选取 n 个字符
+// 使用拒绝采样：丢弃大于等于字符集长度整数倍的字节，避免取模造成的偏差
+func randomBackupCodeChars(n int) ([]byte, error) {
+	limit := 256 - 256%len(backupCodeAlphabet)
+	chars := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(chars) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(chars) < n {
+				chars = append(chars, backupCodeAlphabet[int(b)%len(backupCodeAlphabet)])
+			}
+		}
+	}
+	return chars, nil
+}
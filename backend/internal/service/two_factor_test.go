@@ -0,0 +1,65 @@
+package service
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"backend/internal/config"
+)
+
+func TestTOTPSecretEncryption(t *testing.T) {
+	config.AppConfig = &config.Config{JWTSecret: "jwt-secret-1"}
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	stored, err := encryptTOTPSecret(secret)
+	if err != nil {
+		t.Fatalf("encryptTOTPSecret: %v", err)
+	}
+	if !strings.HasPrefix(stored, totpSecretPrefix) || strings.Contains(stored, secret) {
+		t.Fatalf("stored secret must be encrypted, got %q", stored)
+	}
+	got, err := decryptTOTPSecret(stored)
+	if err != nil {
+		t.Fatalf("decryptTOTPSecret: %v", err)
+	}
+	if got != secret {
+		t.Fatalf("secret = %q, want %q", got, secret)
+	}
+
+	// 以前保存的明文密钥原样返回（验证成功后重新加密）
+	if got, err := decryptTOTPSecret(secret); err != nil || got != secret {
+		t.Fatalf("legacy secret = %q, %v, want %q", got, err, secret)
+	}
+}
+
+func TestDecryptTOTPSecretRejectsInvalidData(t *testing.T) {
+	config.AppConfig = &config.Config{JWTSecret: "jwt-secret-1"}
+	stored, err := encryptTOTPSecret("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	if err != nil {
+		t.Fatalf("encryptTOTPSecret: %v", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, totpSecretPrefix))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	raw[len(raw)-1] ^= 0x01
+
+	tests := []struct {
+		name   string
+		stored string
+		secret string
+	}{
+		{name: "tampered", stored: totpSecretPrefix + base64.StdEncoding.EncodeToString(raw), secret: "jwt-secret-1"},
+		{name: "invalid base64", stored: totpSecretPrefix + "!!!", secret: "jwt-secret-1"},
+		{name: "rotated jwt secret", stored: stored, secret: "jwt-secret-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig = &config.Config{JWTSecret: tt.secret}
+			if _, err := decryptTOTPSecret(tt.stored); err == nil {
+				t.Fatalf("expected decryption to fail")
+			}
+		})
+	}
+}
@@ -5,9 +5,11 @@ import (
 
 	"backend/internal/models"
 	"backend/internal/database"
+
+	"gorm.io/gorm"
 )
 
-// CreateUser 创建新用户
+// CreateUser 创建新用户，并在同一事务中发送邮箱验证邮件
 func CreateUser(user *models.User) error {
 	// 1. 连接数据库
 	db := database.GetDB()
@@ -25,8 +27,13 @@ func CreateUser(user *models.User) error {
 		return errors.New("user with this username already exists")
 	}
 	
-	// 3. 创建用户
-	return db.Create(user).Error
+	// 3. 创建用户并发送验证邮件
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return sendVerificationEmail(tx, user)
+	})
 }
 
 // GetUserByEmail 根据邮箱查找用户
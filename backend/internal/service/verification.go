@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/constants"
+	"backend/internal/database"
+	"backend/internal/email"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// createUserToken 生成一次性令牌，同一用途之前未使用的令牌全部作废，返回令牌明文
+func createUserToken(tx *gorm.DB, userID int, purpose string, ttl time.Duration) (string, error) {
+	// 1. 作废之前未使用的令牌
+	if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Delete(&models.UserToken{}).Error; err != nil {
+		return "", err
+	}
+
+	// 2. 生成新令牌（数据库只保存哈希值）
+	plain, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	token := models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(plain),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := tx.Create(&token).Error; err != nil {
+		return "", err
+	}
+	return plain, nil
+}
+
+// consumeUserToken 校验并使用一次性令牌（需在数据库事务中调用）
+func consumeUserToken(tx *gorm.DB, plain string, purpose string) (*models.UserToken, error) {
+	// 1. 查找令牌并加锁，避免同一令牌被并发使用
+	var token models.UserToken
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND purpose = ?", utils.HashToken(plain), purpose).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("invalid or expired token")
+		}
+		return nil, err
+	}
+
+	// 2. 检查是否已使用或已过期
+	if token.UsedAt != nil || time.Now().After(token.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired token")
+	}
+
+	// 3. 标记为已使用
+	if err := tx.Model(&token).Update("used_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// checkTokenResendCooldown 检查距离上次发送同一用途的令牌是否已超过冷却时间
+func checkTokenResendCooldown(tx *gorm.DB, userID int, purpose string) error {
+	var last models.UserToken
+	err := tx.Where("user_id = ? AND purpose = ?", userID, purpose).Order("created_at DESC").First(&last).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if time.Since(last.CreatedAt) < constants.TokenResendCooldownSeconds*time.Second {
+		return fmt.Errorf("please wait before requesting another email")
+	}
+	return nil
+}
+
+// sendVerificationEmail 生成邮箱验证令牌并写入发件箱（需在数据库事务中调用）
+func sendVerificationEmail(tx *gorm.DB, user *models.User) error {
+	ttl := constants.EmailVerificationExpiryHours * time.Hour
+	token, err := createUserToken(tx, user.ID, constants.TokenPurposeEmailVerification, ttl)
+	if err != nil {
+		return err
+	}
+
+	return EnqueueEmail(tx, user.Email, email.TemplateVerifyEmail, map[string]interface{}{
+		"Username":  user.Username,
+		"Link":      config.AppConfig.AppBaseURL + "/verify-email?token=" + url.QueryEscape(token),
+		"ExpiresIn": fmt.Sprintf("%d hours", constants.EmailVerificationExpiryHours),
+	})
+}
+
+// VerifyEmail 使用验证令牌确认邮箱
+func VerifyEmail(token string) (*models.User, error) {
+	db := database.GetDB()
+
+	var user models.User
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 1. 校验并使用令牌
+		userToken, err := consumeUserToken(tx, token, constants.TokenPurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		// 2. 标记邮箱已验证
+		if err := tx.First(&user, userToken.UserID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&user).Update("email_verified", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// ResendVerificationEmail 重新发送邮箱验证邮件
+func ResendVerificationEmail(userID int) error {
+	db := database.GetDB()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 查询用户
+		var user models.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return err
+		}
+
+		// 2. 已验证的邮箱不需要再发送
+		if user.EmailVerified {
+			return fmt.Errorf("email already verified")
+		}
+
+		// 3. 限制发送频率
+		if err := checkTokenResendCooldown(tx, user.ID, constants.TokenPurposeEmailVerification); err != nil {
+			return err
+		}
+
+		return sendVerificationEmail(tx, &user)
+	})
+}
+
+// IsEmailVerified 判断用户邮箱是否已验证
+func IsEmailVerified(userID int) (bool, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return false, err
+	}
+	return user.EmailVerified, nil
+}
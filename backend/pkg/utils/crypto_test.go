@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptSecretRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		plaintext []byte
+	}{
+		{name: "empty", plaintext: []byte{}},
+		{name: "totp secret", plaintext: []byte(rfc6238Secret)},
+		{name: "multi-byte", plaintext: []byte("两步验证密钥")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ciphertext, err := EncryptSecret("secret-1", tt.plaintext)
+			if err != nil {
+				t.Fatalf("EncryptSecret: %v", err)
+			}
+			plaintext, err := DecryptSecret("secret-1", ciphertext)
+			if err != nil {
+				t.Fatalf("DecryptSecret: %v", err)
+			}
+			if !bytes.Equal(plaintext, tt.plaintext) {
+				t.Fatalf("plaintext = %q, want %q", plaintext, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestEncryptSecretUsesRandomNonce(t *testing.T) {
+	first, err := EncryptSecret("secret-1", []byte(rfc6238Secret))
+	if err != nil {
+		t.Fatalf("EncryptSecret: %v", err)
+	}
+	second, err := EncryptSecret("secret-1", []byte(rfc6238Secret))
+	if err != nil {
+		t.Fatalf("EncryptSecret: %v", err)
+	}
+	if bytes.Equal(first, second) {
+		t.Fatalf("encrypting the same plaintext twice must not produce the same ciphertext")
+	}
+}
+
+func TestDecryptSecretRejectsTampering(t *testing.T) {
+	ciphertext, err := EncryptSecret("secret-1", []byte(rfc6238Secret))
+	if err != nil {
+		t.Fatalf("EncryptSecret: %v", err)
+	}
+
+	// 修改任意一个字节（nonce、密文或认证标签）都必须解密失败
+	for i := range ciphertext {
+		tampered := bytes.Clone(ciphertext)
+		tampered[i] ^= 0x01
+		if _, err := DecryptSecret("secret-1", tampered); err == nil {
+			t.Fatalf("expected tampered byte %d to be rejected", i)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		secret     string
+		ciphertext []byte
+	}{
+		{name: "wrong secret", secret: "secret-2", ciphertext: ciphertext},
+		{name: "truncated", secret: "secret-1", ciphertext: ciphertext[:len(ciphertext)-1]},
+		{name: "shorter than nonce", secret: "secret-1", ciphertext: ciphertext[:4]},
+		{name: "empty", secret: "secret-1", ciphertext: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecryptSecret(tt.secret, tt.ciphertext); err == nil {
+				t.Fatalf("expected decryption to fail")
+			}
+		})
+	}
+}
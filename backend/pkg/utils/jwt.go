@@ -4,36 +4,84 @@ import (
 	"errors"
 	"time"
 
-	"backend/internal/config"
+	"backend/internal/constants"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 // Claims JWT claims结构
 type Claims struct {
-	UserID int `json:"user_id"`
+	UserID    int    `json:"user_id"`
+	SessionID string `json:"sid,omitempty"`     // 所属会话，会话注销后令牌失效
+	Role      string `json:"role,omitempty"`    // 用户角色（签发时的角色，角色变更后会注销用户的会话）
+	Purpose   string `json:"purpose,omitempty"` // 令牌用途，访问令牌为空（例如两步验证的挑战令牌为 2fa_challenge）
 	jwt.RegisteredClaims
 }
 
-// GenerateToken 生成JWT token
-func GenerateToken(userID int) (string, error) {
-	// 设置过期时间为24小时
-	expirationTime := time.Now().Add(24 * time.Hour)
+// GenerateToken 生成JWT访问令牌（短期有效，过期后使用刷新令牌换取）
+func GenerateToken(userID int, sessionID string, role string) (string, error) {
+	return signClaims(&Claims{
+		UserID:    userID,
+		SessionID: sessionID,
+		Role:      role,
+	}, constants.AccessTokenExpiryMinutes*time.Minute)
+}
+
+// GenerateChallengeToken 生成两步验证的挑战令牌（密码验证通过后签发，只能用于提交验证码）
+func GenerateChallengeToken(userID int) (string, error) {
+	return signClaims(&Claims{
+		UserID:  userID,
+		Purpose: constants.TokenPurposeTwoFactorChallenge,
+	}, constants.TwoFactorChallengeExpiryMinutes*time.Minute)
+}
+
+// ValidateToken 验证JWT访问令牌
+func ValidateToken(tokenString string) (*Claims, error) {
+	claims, err := parseClaims(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	// 挑战令牌等特殊用途的令牌不能作为访问令牌使用
+	if claims.Purpose != "" {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
 
-	// 创建claims
-	claims := &Claims{
-		UserID: userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+// ValidateChallengeToken 验证两步验证的挑战令牌
+func ValidateChallengeToken(tokenString string) (*Claims, error) {
+	claims, err := parseClaims(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != constants.TokenPurposeTwoFactorChallenge {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+// signClaims 设置过期时间并签名
+func signClaims(claims *Claims, expiry time.Duration) (string, error) {
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+		IssuedAt:  jwt.NewNumericDate(now),
 	}
 
-	// 创建token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	// 使用当前的签名密钥，kid 标识密钥，验证时据此查找公钥
+	key, err := currentSigningKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = key.ID
 
 	// 签名token
-	tokenString, err := token.SignedString([]byte(config.AppConfig.JWTSecret))
+	tokenString, err := token.SignedString(key.PrivateKey)
 	if err != nil {
 		return "", err
 	}
@@ -41,14 +89,15 @@ func GenerateToken(userID int) (string, error) {
 	return tokenString, nil
 }
 
-// ValidateToken 验证JWT token
-func ValidateToken(tokenString string) (*Claims, error) {
+// parseClaims 解析并验证签名和过期时间
+func parseClaims(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
-	// 解析token
+	// 解析token（只接受 EdDSA，防止算法替换攻击；根据 kid 查找公钥，轮换后的旧密钥在过期前仍然有效）
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(config.AppConfig.JWTSecret), nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		return verificationKey(kid)
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}))
 
 	if err != nil {
 		return nil, err
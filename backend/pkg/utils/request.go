@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// trustedProxies 可信的反向代理网段，只有来自这些地址的请求才使用代理设置的请求头
+var trustedProxies []*net.IPNet
+
+// SetTrustedProxies 设置可信的反向代理（逗号分隔的IP或CIDR，如 "127.0.0.1,10.0.0.0/8"），服务启动时调用
+func SetTrustedProxies(list string) error {
+	var nets []*net.IPNet
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy %q", entry)
+		}
+		nets = append(nets, ipNet)
+	}
+	trustedProxies = nets
+	return nil
+}
+
+// isTrustedProxy 判断地址是否为可信的反向代理
+func isTrustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range trustedProxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP 获取直接连接的地址（不含端口）
+func remoteIP(r *http.Request) string {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return remote
+}
+
+// FromTrustedProxy 判断请求是否直接来自可信的反向代理（只有这时才信任代理设置的请求头）
+func FromTrustedProxy(r *http.Request) bool {
+	return isTrustedProxy(remoteIP(r))
+}
+
+// ClientIP 获取请求的客户端IP
+// 部署时后端位于 Nginx 之后：只有直接连接来自可信代理时才使用 X-Real-IP / X-Forwarded-For，
+// 否则客户端可以伪造这些请求头绕过按IP的登录限制
+func ClientIP(r *http.Request) string {
+	remote := remoteIP(r)
+	if !isTrustedProxy(remote) {
+		return remote
+	}
+
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		return ip
+	}
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		// 从右向左跳过可信代理，第一个不可信的地址是客户端（更左边的地址可能是客户端伪造的）
+		parts := strings.Split(forwarded, ",")
+		for i := len(parts) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(parts[i])
+			if ip != "" && !isTrustedProxy(ip) {
+				return ip
+			}
+		}
+	}
+	return remote
+}
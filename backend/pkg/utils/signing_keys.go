@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"backend/internal/constants"
+)
+
+// SigningKey 内存中的 JWT 签名密钥
+type SigningKey struct {
+	ID         string
+	PrivateKey ed25519.PrivateKey // 只用于验证的旧密钥可以为空
+	PublicKey  ed25519.PublicKey
+}
+
+// JWK JWKS 中公开的公钥
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
+
+// JWKS 公钥集合（/.well-known/jwks.json）
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+var keyStore struct {
+	mu      sync.RWMutex
+	current *SigningKey
+	keys    map[string]ed25519.PublicKey
+}
+
+// keyReload 遇到未知 kid 时重新加载密钥（其他实例轮换后生成的新密钥，在下次定期刷新之前也能验证）
+// mu 保证同一时间只有一次加载，其他请求等待其完成后直接使用结果
+var keyReload struct {
+	mu     sync.Mutex
+	reload func() error
+	last   time.Time
+}
+
+// SetSigningKeyReloader 设置重新加载密钥的函数（由 service 层调用）
+func SetSigningKeyReloader(reload func() error) {
+	keyReload.mu.Lock()
+	defer keyReload.mu.Unlock()
+	keyReload.reload = reload
+}
+
+// SetSigningKeys 设置当前的签名密钥和所有可用于验证的密钥（由 service 层加载后调用）
+func SetSigningKeys(current SigningKey, verificationKeys []SigningKey) {
+	keys := make(map[string]ed25519.PublicKey, len(verificationKeys)+1)
+	for _, k := range verificationKeys {
+		keys[k.ID] = k.PublicKey
+	}
+	keys[current.ID] = current.PublicKey
+
+	keyStore.mu.Lock()
+	defer keyStore.mu.Unlock()
+	keyStore.current = &current
+	keyStore.keys = keys
+}
+
+// currentSigningKey 获取当前用于签名的密钥
+func currentSigningKey() (*SigningKey, error) {
+	keyStore.mu.RLock()
+	defer keyStore.mu.RUnlock()
+	if keyStore.current == nil {
+		return nil, errors.New("signing key not loaded")
+	}
+	return keyStore.current, nil
+}
+
+// verificationKey 根据 kid 获取验证用的公钥，未知的 kid 重新加载一次密钥后再查找
+func verificationKey(kid string) (ed25519.PublicKey, error) {
+	if key, ok := lookupVerificationKey(kid); ok {
+		return key, nil
+	}
+	reloadSigningKeys()
+	if key, ok := lookupVerificationKey(kid); ok {
+		return key, nil
+	}
+	return nil, errors.New("unknown signing key")
+}
+
+// lookupVerificationKey 在已加载的密钥中查找 kid
+func lookupVerificationKey(kid string) (ed25519.PublicKey, bool) {
+	keyStore.mu.RLock()
+	defer keyStore.mu.RUnlock()
+	key, ok := keyStore.keys[kid]
+	return key, ok
+}
+
+// reloadSigningKeys 重新加载密钥，距离上次加载不足 SigningKeyReloadMinSeconds 时跳过
+func reloadSigningKeys() {
+	keyReload.mu.Lock()
+	defer keyReload.mu.Unlock()
+
+	if keyReload.reload == nil || time.Since(keyReload.last) < constants.SigningKeyReloadMinSeconds*time.Second {
+		return
+	}
+	keyReload.last = time.Now()
+	if err := keyReload.reload(); err != nil {
+		log.Printf("⚠️  Failed to reload signing keys: %v", err)
+	}
+}
+
+// PublicJWKS 返回所有可用于验证的公钥
+func PublicJWKS() JWKS {
+	keyStore.mu.RLock()
+	defer keyStore.mu.RUnlock()
+
+	jwks := JWKS{Keys: make([]JWK, 0, len(keyStore.keys))}
+	for kid, key := range keyStore.keys {
+		jwks.Keys = append(jwks.Keys, JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+			Kid: kid,
+			Alg: "EdDSA",
+			Use: "sig",
+		})
+	}
+	sort.Slice(jwks.Keys, func(i, j int) bool { return jwks.Keys[i].Kid < jwks.Keys[j].Kid })
+	return jwks
+}
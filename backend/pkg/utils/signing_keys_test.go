@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestSigningKey(t *testing.T, id string) SigningKey {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return SigningKey{ID: id, PrivateKey: priv, PublicKey: pub}
+}
+
+// setTestReloader 设置重新加载函数（加载时切换到 rotated 密钥），返回调用次数
+func setTestReloader(t *testing.T, current, rotated SigningKey) *atomic.Int32 {
+	t.Helper()
+
+	SetSigningKeys(current, nil)
+	calls := &atomic.Int32{}
+	SetSigningKeyReloader(func() error {
+		calls.Add(1)
+		SetSigningKeys(rotated, []SigningKey{current})
+		return nil
+	})
+	keyReload.last = time.Time{}
+	t.Cleanup(func() { SetSigningKeyReloader(nil) })
+	return calls
+}
+
+func TestVerificationKeyReloadsOnUnknownKid(t *testing.T) {
+	current, rotated := newTestSigningKey(t, "key-1"), newTestSigningKey(t, "key-2")
+	calls := setTestReloader(t, current, rotated)
+
+	// 已知的 kid 不触发加载
+	if _, err := verificationKey("key-1"); err != nil {
+		t.Fatalf("verificationKey(key-1): %v", err)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Fatalf("reload calls = %d, want 0", n)
+	}
+
+	// 其他实例轮换后签发的令牌：加载后可以验证
+	key, err := verificationKey("key-2")
+	if err != nil {
+		t.Fatalf("verificationKey(key-2): %v", err)
+	}
+	if !key.Equal(rotated.PublicKey) {
+		t.Fatalf("unexpected key for key-2")
+	}
+
+	// 最小间隔内不再加载，伪造的 kid 直接失败
+	if _, err := verificationKey("forged"); err == nil {
+		t.Fatalf("expected unknown kid to be rejected")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("reload calls = %d, want 1", n)
+	}
+}
+
+func TestVerificationKeyReloadIsSingleFlight(t *testing.T) {
+	current, rotated := newTestSigningKey(t, "key-1"), newTestSigningKey(t, "key-2")
+	calls := setTestReloader(t, current, rotated)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := verificationKey("key-2"); err != nil {
+				t.Errorf("verificationKey(key-2): %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("reload calls = %d, want 1", n)
+	}
+}
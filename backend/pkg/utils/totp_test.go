@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret RFC 6238 附录 B 中 SHA1 测试向量使用的密钥 "12345678901234567890"（base32 编码）
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTPRFC6238Vectors(t *testing.T) {
+	// 附录 B 中的 8 位验证码取后 6 位
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+		{unix: 20000000000, code: "353130"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			now := time.Unix(tt.unix, 0)
+			step, ok := ValidateTOTP(rfc6238Secret, tt.code, now)
+			if !ok {
+				t.Fatalf("expected code %s to be valid at %d", tt.code, tt.unix)
+			}
+			if want := tt.unix / totpPeriod; step != want {
+				t.Fatalf("step = %d, want %d", step, want)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPSkewAndInput(t *testing.T) {
+	// 1111111109 与 1111111111 分别位于相邻的两个时间步
+	now := time.Unix(1111111111, 0)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		now    time.Time
+		want   bool
+	}{
+		{name: "previous step", secret: rfc6238Secret, code: "081804", now: now, want: true},
+		{name: "two steps old", secret: rfc6238Secret, code: "081804", now: now.Add(2 * totpPeriod * time.Second), want: false},
+		{name: "surrounding spaces", secret: rfc6238Secret, code: " 050471 ", now: now, want: true},
+		{name: "lowercase secret", secret: "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", code: "050471", now: now, want: true},
+		{name: "wrong code", secret: rfc6238Secret, code: "050472", now: now, want: false},
+		{name: "8 digit code", secret: rfc6238Secret, code: "14050471", now: now, want: false},
+		{name: "empty code", secret: rfc6238Secret, code: "", now: now, want: false},
+		{name: "invalid secret", secret: "not base32!", code: "050471", now: now, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := ValidateTOTP(tt.secret, tt.code, tt.now); ok != tt.want {
+				t.Fatalf("ValidateTOTP = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
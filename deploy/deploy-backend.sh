@@ -15,6 +15,16 @@ if [ ! -f .env ]; then
     exit 1
 fi
 
+# 检查运行环境：生产服务器不能以开发模式运行（开发模式允许占位密钥）
+if grep -Eq '^[[:space:]]*APP_ENV[[:space:]]*=[[:space:]]*"?development"?[[:space:]]*$' .env; then
+    echo "错误: .env 中 APP_ENV=development，生产环境请设置 APP_ENV=production"
+    exit 1
+fi
+if grep -Eq '^[[:space:]]*JWT_SECRET[[:space:]]*=[[:space:]]*"?(your-secret-key-change-this(-in-production)?|dev-jwt-secret-key-do-not-use-in-production)?"?[[:space:]]*$' .env; then
+    echo "错误: .env 中 JWT_SECRET 未设置或仍是示例值，请用 openssl rand -base64 32 生成"
+    exit 1
+fi
+
 # 下载依赖
 echo "下载Go依赖..."
 go mod download
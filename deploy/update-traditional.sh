@@ -25,13 +25,23 @@ echo -e "${GREEN}✓ 代码更新成功${NC}"
 echo ""
 echo -e "${YELLOW}2. 更新后端...${NC}"
 
+# 进入后端目录
+cd ~/SecondHandPlatform/backend/backend
+
+# 检查运行环境：新版本在生产环境拒绝使用开发模式和示例密钥，先检查再停止服务
+if grep -Eq '^[[:space:]]*APP_ENV[[:space:]]*=[[:space:]]*"?development"?[[:space:]]*$' .env; then
+    echo -e "${RED}错误: .env 中 APP_ENV=development，生产环境请设置 APP_ENV=production${NC}"
+    exit 1
+fi
+if grep -Eq '^[[:space:]]*JWT_SECRET[[:space:]]*=[[:space:]]*"?(your-secret-key-change-this(-in-production)?|dev-jwt-secret-key-do-not-use-in-production)?"?[[:space:]]*$' .env; then
+    echo -e "${RED}错误: .env 中 JWT_SECRET 未设置或仍是示例值，请用 openssl rand -base64 32 生成${NC}"
+    exit 1
+fi
+
 # 停止后端服务
 echo "停止后端服务..."
 sudo systemctl stop secondhand-backend
 
-# 进入后端目录
-cd ~/SecondHandPlatform/backend/backend
-
 # 下载依赖
 echo "更新Go依赖..."
 go mod download
@@ -0,0 +1,105 @@
+// ========================================
+// 登录令牌管理
+// ========================================
+// 后端的访问令牌只有 15 分钟有效期，登录时同时返回刷新令牌（refresh_token）。
+// 这里给 axios 安装拦截器：带 Authorization 的请求返回 401 时，
+// 用刷新令牌调用 POST /refresh 换取新令牌（刷新令牌每次都会轮换），然后重试原请求。
+import axios from "axios";
+import { BASE_URL, TOKEN_KEY, REFRESH_TOKEN_KEY } from "./constants";
+
+// 保存登录后返回的访问令牌和刷新令牌
+export function saveTokens(token, refreshToken) {
+  if (token) localStorage.setItem(TOKEN_KEY, token);
+  if (refreshToken) localStorage.setItem(REFRESH_TOKEN_KEY, refreshToken);
+}
+
+// 清除本地保存的令牌
+export function clearTokens() {
+  localStorage.removeItem(TOKEN_KEY);
+  localStorage.removeItem(REFRESH_TOKEN_KEY);
+  sessionStorage.removeItem(TOKEN_KEY);
+}
+
+// 退出登录：通知后端注销当前会话（失败也清除本地令牌）
+export async function logout() {
+  const token = localStorage.getItem(TOKEN_KEY);
+  try {
+    if (token) {
+      await axios.post(`${BASE_URL}/logout`, null, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+    }
+  } catch (err) {
+    // 令牌已失效时后端会返回 401，忽略即可
+  } finally {
+    clearTokens();
+  }
+}
+
+// 正在进行的刷新请求（多个请求同时 401 时只刷新一次，因为刷新令牌只能使用一次）
+let refreshPromise = null;
+
+function refreshAccessToken() {
+  if (!refreshPromise) {
+    const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY);
+    refreshPromise = axios
+      .post(`${BASE_URL}/refresh`, { refresh_token: refreshToken })
+      .then((res) => {
+        const { token, refresh_token } = res.data.data;
+        saveTokens(token, refresh_token);
+        return token;
+      })
+      .finally(() => {
+        refreshPromise = null;
+      });
+  }
+  return refreshPromise;
+}
+
+// 安装拦截器，返回卸载函数
+// onSessionExpired：刷新失败（刷新令牌过期或会话已注销）时调用，用于切换到未登录状态
+export function setupAuthInterceptors(onSessionExpired) {
+  // 1. 请求前：组件可能在令牌刷新之前读取了旧令牌，统一替换为最新的访问令牌
+  const requestInterceptor = axios.interceptors.request.use((config) => {
+    const token = localStorage.getItem(TOKEN_KEY);
+    if (token && config.headers && config.headers.Authorization) {
+      config.headers.Authorization = `Bearer ${token}`;
+    }
+    return config;
+  });
+
+  // 2. 返回 401 时刷新令牌并重试一次
+  const responseInterceptor = axios.interceptors.response.use(
+    (response) => response,
+    async (error) => {
+      const config = error.config;
+      const isRefreshRequest = config && config.url === `${BASE_URL}/refresh`;
+      if (
+        error.response?.status !== 401 ||
+        !config ||
+        isRefreshRequest ||
+        config._retried ||
+        !config.headers?.Authorization ||
+        !localStorage.getItem(REFRESH_TOKEN_KEY)
+      ) {
+        return Promise.reject(error);
+      }
+
+      try {
+        const token = await refreshAccessToken();
+        config._retried = true;
+        config.headers.Authorization = `Bearer ${token}`;
+        return axios(config);
+      } catch (refreshError) {
+        clearTokens();
+        if (onSessionExpired) onSessionExpired();
+        return Promise.reject(error);
+      }
+    }
+  );
+
+  return () => {
+    axios.interceptors.request.eject(requestInterceptor);
+    axios.interceptors.response.eject(responseInterceptor);
+  };
+}
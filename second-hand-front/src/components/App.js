@@ -3,6 +3,7 @@ import { Routes, Route } from "react-router-dom";
 import Main from "./Main";
 
 import { TOKEN_KEY } from "../constants";
+import { saveTokens, logout, setupAuthInterceptors } from "../auth";
 
 function App() {
   const [isLoggedIn, setIsLoggedIn] = useState(
@@ -14,15 +15,20 @@ function App() {
     setIsLoggedIn(token ? true : false);
   }, []);
 
-  const handleLoggedIn = (token) => {
+  // 访问令牌过期时自动刷新；刷新令牌也失效时切换到未登录状态
+  useEffect(() => {
+    return setupAuthInterceptors(() => setIsLoggedIn(false));
+  }, []);
+
+  const handleLoggedIn = (token, refreshToken) => {
     if (token) {
-      localStorage.setItem(TOKEN_KEY, token);
+      saveTokens(token, refreshToken);
       setIsLoggedIn(true);
     }
   };
 
-  const handleLogout = () => {
-    localStorage.removeItem(TOKEN_KEY);
+  const handleLogout = async () => {
+    await logout();
     setIsLoggedIn(false);
   };
 
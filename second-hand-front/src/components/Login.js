@@ -61,10 +61,11 @@ function Login({ handleLoggedIn }) {
       // 4. 处理后端响应
       // 成功响应格式：{ success: true, data: { token: "...", user: {...} } }
       if (res.data.success) {
-        const token = res.data.data.token; // 从响应中提取JWT token
+        const token = res.data.data.token; // 从响应中提取JWT token（访问令牌，15分钟有效）
+        const refreshToken = res.data.data.refresh_token; // 刷新令牌，用于访问令牌过期后换取新令牌
         // 调用父组件传入的回调函数，将token传递给App组件
         // App组件会保存token到localStorage，并更新登录状态
-        handleLoggedIn(token);
+        handleLoggedIn(token, refreshToken);
       } else {
         // 如果success为false，显示后端返回的错误信息
         setError(res.data.message);
@@ -1,7 +1,7 @@
 // npm i @mui/material @emotion/react @emotion/styled @mui/icons-material
 import React from "react";
 import { Link as RouterLink, useNavigate } from "react-router-dom";
-import { TOKEN_KEY } from "../constants";
+import { logout } from "../auth";
 
 import AppBar from "@mui/material/AppBar";
 import Toolbar from "@mui/material/Toolbar";
@@ -11,9 +11,13 @@ import Box from "@mui/material/Box";
 function NavBar({ handleLogout }) {
     const navigate = useNavigate();
 
-    const onLogout = () => {
-        localStorage.removeItem(TOKEN_KEY);
-        if (handleLogout) handleLogout();
+    const onLogout = async () => {
+        // handleLogout 会通知后端注销会话并清除本地令牌
+        if (handleLogout) {
+            await handleLogout();
+        } else {
+            await logout();
+        }
         navigate("/login");
     };
 
@@ -10,6 +10,7 @@ import {
 } from "@ant-design/icons";
 import { useLocation, useNavigate } from "react-router-dom";
 import "../styles/NavBarNew.css";
+import { logout } from "../auth";
 
 const { Header } = Layout;
 const { Text } = Typography;
@@ -27,9 +28,8 @@ function NavBar() {
     return "home";
   }, [location.pathname]);
 
-  const onLogout = () => {
-    localStorage.removeItem("token");
-    sessionStorage.removeItem("token");
+  const onLogout = async () => {
+    await logout();
     window.location.reload();
   };
 
@@ -9,7 +9,8 @@ import {
 import axios from "axios";
 import React, { useState } from "react";
 import { useNavigate } from "react-router-dom"; // 1. 引入跳转钩子
-import { BASE_URL, TOKEN_KEY } from "../constants";
+import { BASE_URL } from "../constants";
+import { saveTokens } from "../auth";
 import "../styles/SignUp.css";
 
 import NavBar from "./NavBarNew";
@@ -50,9 +51,9 @@ const SignUp = () => {
 
       // 后端返回格式: { success: true, data: { token: "...", user: {...} } }
       if (response.data.success) {
-        const { token } = response.data.data;
-        // 存储token到localStorage
-        localStorage.setItem(TOKEN_KEY, token);
+        const { token, refresh_token } = response.data.data;
+        // 存储token和刷新令牌到localStorage
+        saveTokens(token, refresh_token);
 
         alert("Registration Successful!");
         navigate("/login"); // 注册成功后跳转登录页
@@ -7,6 +7,7 @@
 //   "price": 300,
 //   "negotiable": true,
 //   "zipCode": "12345",
+//   "category": "electronics",
 // }
 // images: multipart/form-data
 
@@ -26,6 +27,7 @@ import {
   Input,
   InputNumber,
   Radio,
+  Select,
   Upload,
   message,
 } from "antd";
@@ -35,6 +37,18 @@ const MAX_SIZE_MB = 5;
 const { Dragger } = Upload;
 const { TextArea } = Input;
 
+// 与后端 constants.PostCategory* 保持一致
+const CATEGORY_OPTIONS = [
+  { value: "electronics", label: "Electronics" },
+  { value: "furniture", label: "Furniture" },
+  { value: "clothing", label: "Clothing" },
+  { value: "books", label: "Books" },
+  { value: "sports", label: "Sports & Outdoors" },
+  { value: "home", label: "Home" },
+  { value: "vehicles", label: "Vehicles" },
+  { value: "other", label: "Other" },
+];
+
 function UploadPage({ handleLogout }) {
   const navigate = useNavigate();
   const [form] = Form.useForm();
@@ -106,6 +120,7 @@ function UploadPage({ handleLogout }) {
       fd.append("price", String(values.price));
       fd.append("negotiable", String(values.negotiable));
       fd.append("zip_code", values.zipCode); // 后端字段名为zip_code
+      fd.append("category", values.category);
 
       fileList.forEach((f) => {
         if (f.originFileObj) fd.append("images", f.originFileObj);
@@ -148,6 +163,7 @@ function UploadPage({ handleLogout }) {
             }}
             initialValues={{
               negotiable: true,
+              category: "other",
               price: 0,
             }}
           >
@@ -166,6 +182,21 @@ function UploadPage({ handleLogout }) {
               </div>
             </div>
 
+            <div className="sell-row">
+              <div className="sell-row-label">
+                Category<span className="req">*</span>
+              </div>
+              <div className="sell-row-field">
+                <Form.Item
+                  name="category"
+                  rules={[{ required: true, message: "Category is required" }]}
+                  style={{ marginBottom: 0 }}
+                >
+                  <Select options={CATEGORY_OPTIONS} />
+                </Form.Item>
+              </div>
+            </div>
+
             <div className="sell-upload-center">
               <div className="sell-upload-hint">
                 Images<span className="req">*</span> (min 1, max 5)
@@ -1,3 +1,4 @@
 export const TOKEN_KEY = "token";
+export const REFRESH_TOKEN_KEY = "refresh_token";
 export const BASE_URL = "http://localhost:8080";
 export const USE_MOCK = false;
\ No newline at end of file